@@ -1,32 +1,79 @@
 package api
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/bito_interview/model"
 	storage "github.com/bito_interview/storage"
-	"github.com/go-playground/validator/v10"
+	"github.com/bito_interview/webhook"
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 )
 
-var validate = validator.New()
+var validate = model.NewValidator()
 
-func NewRouter() *mux.Router {
+type handler struct {
+	store storage.Store
+	hooks *webhook.Dispatcher
+}
+
+// RouterOption configures the router.
+type RouterOption func(*handler)
+
+// WithWebhooks serves the webhook subscriptions of the dispatcher, which are
+// not served by default.
+func WithWebhooks(hooks *webhook.Dispatcher) RouterOption {
+	return func(h *handler) {
+		h.hooks = hooks
+	}
+}
+
+// NewRouter returns the HTTP router serving the candidate pool of the given store.
+func NewRouter(store storage.Store, opts ...RouterOption) *mux.Router {
+	h := &handler{store: store}
+	for _, opt := range opts {
+		opt(h)
+	}
 	router := mux.NewRouter()
-	router.HandleFunc("/add-and-match", AddSinglePersonAndMatch).Methods(http.MethodPost)
-	router.HandleFunc("/person/{id}", RemoveSinglePerson).Methods(http.MethodDelete)
-	router.HandleFunc("/person/{id}/matches", QuerySinglePeople).Methods(http.MethodGet)
+	router.HandleFunc("/add-and-match", h.AddSinglePersonAndMatch).Methods(http.MethodPost)
+	router.HandleFunc("/people", h.ListPeople).Methods(http.MethodGet)
+	router.HandleFunc("/people:import", h.ImportPeople).Methods(http.MethodPost)
+	router.HandleFunc("/people:export", h.ExportPeople).Methods(http.MethodGet)
+	router.HandleFunc("/person/{id}", h.GetSinglePerson).Methods(http.MethodGet)
+	router.HandleFunc("/person/{id}", h.RemoveSinglePerson).Methods(http.MethodDelete)
+	router.HandleFunc("/person/{id}", h.UpdateSinglePerson).Methods(http.MethodPatch)
+	router.HandleFunc("/person/{id}/match", h.MatchSinglePerson).Methods(http.MethodPost)
+	router.HandleFunc("/person/{id}/matches", h.QuerySinglePeople).Methods(http.MethodGet)
+	router.HandleFunc("/person/{id}/history", h.QueryMatchHistory).Methods(http.MethodGet)
+	router.HandleFunc("/person/{id}/events", h.StreamEvents).Methods(http.MethodGet)
+	router.HandleFunc("/person/{id}/like/{targetId}", h.LikePerson).Methods(http.MethodPost)
+	router.HandleFunc("/person/{id}/pass/{targetId}", h.PassPerson).Methods(http.MethodPost)
+	router.HandleFunc("/person/{id}/block/{targetId}", h.BlockPerson).Methods(http.MethodPost)
+	router.HandleFunc("/person/{id}/report/{targetId}", h.ReportPerson).Methods(http.MethodPost)
+	router.HandleFunc("/admin/reports", h.QueryReports).Methods(http.MethodGet)
+	router.HandleFunc("/matches/{matchId}", h.GetMatch).Methods(http.MethodGet)
+	if h.hooks != nil {
+		router.HandleFunc("/webhooks", h.CreateWebhook).Methods(http.MethodPost)
+		router.HandleFunc("/webhooks", h.QueryWebhooks).Methods(http.MethodGet)
+		router.HandleFunc("/webhooks/dead-letters", h.QueryDeadLetters).Methods(http.MethodGet)
+		router.HandleFunc("/webhooks/{webhookId}", h.RemoveWebhook).Methods(http.MethodDelete)
+		router.HandleFunc("/webhooks/{webhookId}/deliveries", h.QueryWebhookDeliveries).Methods(http.MethodGet)
+	}
 	router.HandleFunc("/swagger/{any}", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8080/v1/swagger/doc.json"))).Methods(http.MethodGet)
 	return router
 }
 
-func AddSinglePersonAndMatch(w http.ResponseWriter, r *http.Request) {
+func (h *handler) AddSinglePersonAndMatch(w http.ResponseWriter, r *http.Request) {
 	if r.Body == nil {
 		http.Error(w, "request json body missing", http.StatusBadRequest)
 		return
@@ -47,12 +94,16 @@ func AddSinglePersonAndMatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	storagePerson := storage.Add(IdGenerator.GenerateKey(), newPerson)
+	storagePerson, err := h.store.Add(IdGenerator.GenerateKey(), newPerson)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	resp := AddAndMatchResponse{Self: &PersonResponse{ID: storagePerson.ID, PersonAttributes: storagePerson.PersonAttributes}}
-	matchPerson, err := storage.Match(storagePerson.ID)
+	resp := AddAndMatchResponse{Self: personResponseOf(storagePerson)}
+	matchPerson, err := h.store.Match(storagePerson.ID)
 	if err == nil {
-		resp.Match = &PersonResponse{ID: matchPerson.ID, PersonAttributes: matchPerson.PersonAttributes}
+		resp.Match = personResponseOf(matchPerson)
 	}
 
 	jsonResp, err := json.Marshal(resp)
@@ -63,14 +114,215 @@ func AddSinglePersonAndMatch(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, string(jsonResp))
 }
 
-func RemoveSinglePerson(w http.ResponseWriter, r *http.Request) {
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// intQuery returns the non-negative integer query parameter, 0 when it is absent.
+func intQuery(r *http.Request, name string) (int, error) {
+	if !r.URL.Query().Has(name) {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("query parameter `%s` non-negative number is expected", name)
+	}
+	return value, nil
+}
+
+// pageSize returns the `limit` query parameter, defaultPageSize when it is absent.
+func pageSize(r *http.Request) (int, error) {
+	if !r.URL.Query().Has("limit") {
+		return defaultPageSize, nil
+	}
+	limit, err := intQuery(r, "limit")
+	if err != nil {
+		return 0, err
+	}
+	if limit < 1 || limit > maxPageSize {
+		return 0, fmt.Errorf("query parameter `limit` between 1 and %d is expected", maxPageSize)
+	}
+	return limit, nil
+}
+
+func (h *handler) ListPeople(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := storage.PeopleFilter{Gender: model.Gender(query.Get("gender")), NamePrefix: query.Get("name")}
+	var err error
+	for name, value := range map[string]*int{
+		"min_height": &filter.MinHeight,
+		"max_height": &filter.MaxHeight,
+		"min_dates":  &filter.MinDates,
+		"max_dates":  &filter.MaxDates,
+	} {
+		if *value, err = intQuery(r, name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	limit, err := pageSize(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var after *storage.Cursor
+	if query.Get("cursor") != "" {
+		after = &storage.Cursor{}
+		if err := decodeCursor(query.Get("cursor"), after); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	page, err := h.store.List(filter, after, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp := &PeoplePage{People: []ProfileResponse{}, Total: page.Total}
+	for _, person := range page.People {
+		resp.People = append(resp.People, ProfileResponse{ID: person.ID, Person: person.Person})
+	}
+	if page.Next != nil {
+		if resp.NextCursor, err = encodeCursor(page.Next); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	jsonResp, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+// maxImportLineSize is the maximum size of a line of the people imported.
+const maxImportLineSize = 1 << 20
+
+// ImportPeople adds the valid people of the newline-delimited JSON body in a
+// single batch, the invalid lines are reported and skipped. The people
+// imported are only matched when the `match` query parameter is true.
+func (h *handler) ImportPeople(w http.ResponseWriter, r *http.Request) {
+	match := false
+	if r.URL.Query().Has("match") {
+		var err error
+		if match, err = strconv.ParseBool(r.URL.Query().Get("match")); err != nil {
+			http.Error(w, "query parameter `match` boolean is expected", http.StatusBadRequest)
+			return
+		}
+	}
+	if r.Body == nil {
+		http.Error(w, "request body missing", http.StatusBadRequest)
+		return
+	}
+	resp := &ImportResponse{People: []ImportedPerson{}, Errors: []LineError{}}
+	var people storage.People
+	var lines []int
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		jsonBytes := bytes.TrimSpace(scanner.Bytes())
+		if len(jsonBytes) == 0 {
+			continue
+		}
+		newPerson := &model.Person{}
+		if err := json.Unmarshal(jsonBytes, newPerson); err != nil {
+			resp.Errors = append(resp.Errors, LineError{Line: line, Error: err.Error()})
+			continue
+		}
+		if err := validate.Struct(newPerson); err != nil {
+			resp.Errors = append(resp.Errors, LineError{Line: line, Error: err.Error()})
+			continue
+		}
+		people = append(people, &storage.Person{ID: IdGenerator.GenerateKey(), Person: *newPerson})
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.store.Import(people); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp.Imported = len(people)
+	for i, person := range people {
+		imported := ImportedPerson{Line: lines[i], ID: person.ID}
+		if match {
+			if matchPerson, err := h.store.Match(person.ID); err == nil {
+				imported.Match = personResponseOf(matchPerson)
+			}
+		}
+		resp.People = append(resp.People, imported)
+	}
+	jsonResp, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+// ExportPeople streams everyone in the pool as JSONL, or as CSV when the
+// client accepts text/csv, writing every person as soon as he/she is read.
+func (h *handler) ExportPeople(w http.ResponseWriter, r *http.Request) {
+	var e exporter
+	if strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="people.csv"`)
+		var err error
+		if e, err = newCSVExporter(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="people.jsonl"`)
+		e = newJSONLExporter(w)
+	}
+	err := h.store.Export(e.write)
+	if err == nil {
+		err = e.flush()
+	}
+	if err != nil {
+		// the response is already under way, the client sees a truncated export.
+		log.Printf("export people: %v", err)
+	}
+}
+
+func (h *handler) GetSinglePerson(w http.ResponseWriter, r *http.Request) {
 	var id string
 	var ok bool
 	if id, ok = mux.Vars(r)["id"]; !ok {
 		http.Error(w, "id is required", http.StatusBadRequest)
 		return
 	}
-	err := storage.Remove(id)
+	person, err := h.store.Get(id)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	jsonResp, err := json.Marshal(ProfileResponse{ID: person.ID, Person: person.Person})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+func (h *handler) RemoveSinglePerson(w http.ResponseWriter, r *http.Request) {
+	var id string
+	var ok bool
+	if id, ok = mux.Vars(r)["id"]; !ok {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+	err := h.store.Remove(id)
 	if err != nil {
 		if err == storage.ErrNotFound {
 			http.Error(w, err.Error(), http.StatusNotFound)
@@ -82,7 +334,68 @@ func RemoveSinglePerson(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, "removed")
 }
 
-func QuerySinglePeople(w http.ResponseWriter, r *http.Request) {
+func (h *handler) UpdateSinglePerson(w http.ResponseWriter, r *http.Request) {
+	var id string
+	var ok bool
+	if id, ok = mux.Vars(r)["id"]; !ok {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+	if r.Body == nil {
+		http.Error(w, "request json body missing", http.StatusBadRequest)
+		return
+	}
+	update := &model.PersonUpdate{}
+	if err := json.NewDecoder(r.Body).Decode(update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validate.Struct(update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	person, err := h.store.Update(id, update)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	jsonResp, err := json.Marshal(ProfileResponse{ID: person.ID, Person: person.Person})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+func (h *handler) MatchSinglePerson(w http.ResponseWriter, r *http.Request) {
+	var id string
+	var ok bool
+	if id, ok = mux.Vars(r)["id"]; !ok {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+	match, err := h.store.Match(id)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	jsonResp, err := json.Marshal(personResponseOf(match))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+func (h *handler) QuerySinglePeople(w http.ResponseWriter, r *http.Request) {
 	var id string
 	var ok bool
 	if id, ok = mux.Vars(r)["id"]; !ok {
@@ -104,18 +417,38 @@ func QuerySinglePeople(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	matches, err := storage.PossibleMatches(id, maxNum)
+	var after *storage.Cursor
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		after = &storage.Cursor{}
+		if err := decodeCursor(cursor, after); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	matches, next, err := h.store.PossibleMatches(id, after, maxNum)
 	if err != nil {
-		if err == storage.ErrNotFound {
+		switch err {
+		case storage.ErrNotFound:
 			http.Error(w, err.Error(), http.StatusNotFound)
-		} else {
+		case storage.ErrCursorOrder:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
 		return
 	}
 	resp := &PossibleMatches{}
+	if next != nil {
+		if resp.NextCursor, err = encodeCursor(next); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 	for _, match := range matches {
-		resp.Matches = append(resp.Matches, PersonResponse{ID: match.ID, PersonAttributes: match.PersonAttributes})
+		candidate := personResponseOf(match.Person)
+		candidate.DistanceKm, candidate.Score = match.DistanceKm, &match.Score
+		resp.Matches = append(resp.Matches, *candidate)
 	}
 	jsonResp, err := json.Marshal(resp)
 	if err != nil {
@@ -124,3 +457,355 @@ func QuerySinglePeople(w http.ResponseWriter, r *http.Request) {
 	}
 	fmt.Fprint(w, string(jsonResp))
 }
+
+func (h *handler) QueryMatchHistory(w http.ResponseWriter, r *http.Request) {
+	var id string
+	var ok bool
+	if id, ok = mux.Vars(r)["id"]; !ok {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+	history, err := h.store.History(id)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	resp := &MatchHistory{Matches: []MatchResponse{}}
+	for _, match := range history {
+		resp.Matches = append(resp.Matches, MatchResponse{MatchRecord: match})
+	}
+	jsonResp, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+func (h *handler) GetMatch(w http.ResponseWriter, r *http.Request) {
+	var matchID string
+	var ok bool
+	if matchID, ok = mux.Vars(r)["matchId"]; !ok {
+		http.Error(w, "match id is required", http.StatusBadRequest)
+		return
+	}
+	match, err := h.store.GetMatch(matchID)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	jsonResp, err := json.Marshal(MatchResponse{MatchRecord: *match})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+// eventsKeepAlive is the interval of the comments keeping the event stream
+// open through proxies while there is no event.
+var eventsKeepAlive = 15 * time.Second
+
+// StreamEvents pushes the match, removal and eviction events of the person
+// as server-sent events. The stream ends once the person left the pool, or
+// when the person falls too far behind the events to be notified.
+func (h *handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	var id string
+	var ok bool
+	if id, ok = mux.Vars(r)["id"]; !ok {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	// subscribing first does not miss the events right after the lookup.
+	events, cancel := h.store.Subscribe(id)
+	defer cancel()
+	if _, err := h.store.Get(id); err != nil {
+		if err == storage.ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			jsonResp, err := json.Marshal(EventResponse{Event: event})
+			if err != nil {
+				log.Printf("stream events of %s: %v", id, err)
+				return
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, jsonResp)
+			if event.Type != storage.EventMatched {
+				flusher.Flush()
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func (h *handler) LikePerson(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, targetID := vars["id"], vars["targetId"]
+	if id == "" || targetID == "" {
+		http.Error(w, "id and target id are required", http.StatusBadRequest)
+		return
+	}
+	match, err := h.store.Like(id, targetID)
+	if err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case storage.ErrSamePerson:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case storage.ErrAlreadyMatched:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	resp := LikeResponse{}
+	if match != nil {
+		resp.Match = &MatchResponse{MatchRecord: *match}
+	}
+	jsonResp, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+func (h *handler) PassPerson(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, targetID := vars["id"], vars["targetId"]
+	if id == "" || targetID == "" {
+		http.Error(w, "id and target id are required", http.StatusBadRequest)
+		return
+	}
+	if err := h.store.Pass(id, targetID); err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case storage.ErrSamePerson:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case storage.ErrAlreadyMatched:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	fmt.Fprint(w, "passed")
+}
+
+func (h *handler) BlockPerson(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, targetID := vars["id"], vars["targetId"]
+	if id == "" || targetID == "" {
+		http.Error(w, "id and target id are required", http.StatusBadRequest)
+		return
+	}
+	if err := h.store.Block(id, targetID); err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case storage.ErrSamePerson:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	fmt.Fprint(w, "blocked")
+}
+
+func (h *handler) ReportPerson(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, targetID := vars["id"], vars["targetId"]
+	if id == "" || targetID == "" {
+		http.Error(w, "id and target id are required", http.StatusBadRequest)
+		return
+	}
+	if r.Body == nil {
+		http.Error(w, "request json body missing", http.StatusBadRequest)
+		return
+	}
+	req := &ReportRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	report, err := h.store.Report(id, targetID, req.Reason, req.Comment)
+	if err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case storage.ErrSamePerson, storage.ErrUnknownReason:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	jsonResp, err := json.Marshal(ReportResponse{Report: *report})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+func (h *handler) QueryReports(w http.ResponseWriter, r *http.Request) {
+	filter := storage.ReportFilter{
+		Reason: storage.ReportReason(r.URL.Query().Get("reason")),
+		Target: r.URL.Query().Get("target"),
+	}
+	if filter.Reason != "" && !filter.Reason.Valid() {
+		http.Error(w, storage.ErrUnknownReason.Error(), http.StatusBadRequest)
+		return
+	}
+	reports, err := h.store.Reports(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp := Reports{Reports: []ReportResponse{}}
+	for _, report := range reports {
+		resp.Reports = append(resp.Reports, ReportResponse{Report: report})
+	}
+	jsonResp, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+func (h *handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Body == nil {
+		http.Error(w, "request json body missing", http.StatusBadRequest)
+		return
+	}
+	req := &WebhookRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	subscription := h.hooks.Subscribe(req.URL, req.Events, req.Secret)
+	jsonResp, err := json.Marshal(WebhookResponse{Subscription: *subscription})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+func (h *handler) QueryWebhooks(w http.ResponseWriter, r *http.Request) {
+	resp := Webhooks{Webhooks: []WebhookResponse{}}
+	for _, subscription := range h.hooks.Subscriptions() {
+		resp.Webhooks = append(resp.Webhooks, WebhookResponse{Subscription: subscription})
+	}
+	jsonResp, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+func (h *handler) RemoveWebhook(w http.ResponseWriter, r *http.Request) {
+	var id string
+	var ok bool
+	if id, ok = mux.Vars(r)["webhookId"]; !ok {
+		http.Error(w, "webhook id is required", http.StatusBadRequest)
+		return
+	}
+	if err := h.hooks.Unsubscribe(id); err != nil {
+		if err == webhook.ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	fmt.Fprint(w, "removed")
+}
+
+func (h *handler) QueryWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	var id string
+	var ok bool
+	if id, ok = mux.Vars(r)["webhookId"]; !ok {
+		http.Error(w, "webhook id is required", http.StatusBadRequest)
+		return
+	}
+	deliveries, err := h.hooks.Deliveries(id)
+	if err != nil {
+		if err == webhook.ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	jsonResp, err := json.Marshal(deliveriesOf(deliveries))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+func (h *handler) QueryDeadLetters(w http.ResponseWriter, r *http.Request) {
+	jsonResp, err := json.Marshal(deliveriesOf(h.hooks.DeadLetters()))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, string(jsonResp))
+}
+
+func deliveriesOf(deliveries []webhook.Delivery) Deliveries {
+	resp := Deliveries{Deliveries: []DeliveryResponse{}}
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, DeliveryResponse{Delivery: delivery})
+	}
+	return resp
+}
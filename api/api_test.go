@@ -6,10 +6,15 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/bito_interview/model"
 	"github.com/bito_interview/storage"
+	"github.com/bito_interview/webhook"
 )
 
 func TestAddSinglePersonAndMatch(t *testing.T) {
@@ -57,6 +62,87 @@ func TestAddSinglePersonAndMatch(t *testing.T) {
 				return &a
 			}(),
 		},
+		{
+			name: "invalid gender",
+			req: newRequest(http.MethodPost, "/add-and-match", bytes.NewBuffer(jsonMarshal(t, &model.Person{
+				PersonAttributes:    model.PersonAttributes{Name: "abc", Height: 100, Gender: "Non Binary"},
+				NumberOfWantedDates: 10,
+			}))),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "non-binary person created with match",
+			person: func() *storage.Person {
+				person := createPerson("2", model.GenderNonBinary, 90, 1)
+				person.Preferences = &model.Preferences{Seeking: []model.Gender{model.GenderNonBinary}}
+				return person
+			}(),
+			req: newRequest(http.MethodPost, "/add-and-match", bytes.NewBuffer(jsonMarshal(t, &model.Person{
+				PersonAttributes:    model.PersonAttributes{Name: "abc", Height: 100, Gender: model.GenderNonBinary},
+				NumberOfWantedDates: 10,
+				Preferences:         &model.Preferences{Seeking: []model.Gender{model.GenderNonBinary}},
+			}))),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				a := `{"self":{"id":"1","name":"abc","height":100,"gender":"non-binary"},"match":{"id":"2","name":"","height":90,"gender":"non-binary"}}`
+				return &a
+			}(),
+		},
+		{
+			name: "invalid birth date",
+			req: newRequest(http.MethodPost, "/add-and-match", bytes.NewBuffer(jsonMarshal(t, &model.Person{
+				PersonAttributes:    model.PersonAttributes{Name: "abc", Height: 100, Gender: model.GenderMale, BirthDate: "01/02/1990"},
+				NumberOfWantedDates: 10,
+			}))),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "future birth date",
+			req: newRequest(http.MethodPost, "/add-and-match", bytes.NewBuffer(jsonMarshal(t, &model.Person{
+				PersonAttributes: model.PersonAttributes{
+					Name: "abc", Height: 100, Gender: model.GenderMale, BirthDate: time.Now().AddDate(0, 0, 2).Format(model.DateLayout),
+				},
+				NumberOfWantedDates: 10,
+			}))),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid location",
+			req: newRequest(http.MethodPost, "/add-and-match", bytes.NewBuffer(jsonMarshal(t, &model.Person{
+				PersonAttributes: model.PersonAttributes{
+					Name: "abc", Height: 100, Gender: model.GenderMale, Location: &model.Location{Latitude: 91, Longitude: 0},
+				},
+				NumberOfWantedDates: 10,
+			}))),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid preferences",
+			req: newRequest(http.MethodPost, "/add-and-match", bytes.NewBuffer(jsonMarshal(t, &model.Person{
+				PersonAttributes:    model.PersonAttributes{Name: "abc", Height: 100, Gender: model.GenderMale},
+				NumberOfWantedDates: 10,
+				Preferences: &model.Preferences{
+					Height:  &model.HeightRange{Min: 100, Max: 90},
+					Age:     &model.AgeRange{Min: 30, Max: 20},
+					Seeking: []model.Gender{"Unknown"},
+				},
+			}))),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "person created with preferences not matched",
+			person: createPerson("2", model.GenderFemale, 90, 1),
+			req: newRequest(http.MethodPost, "/add-and-match", bytes.NewBuffer(jsonMarshal(t, &model.Person{
+				PersonAttributes:    model.PersonAttributes{Name: "abc", Height: 100, Gender: model.GenderMale},
+				NumberOfWantedDates: 10,
+				Preferences:         &model.Preferences{Height: &model.HeightRange{Min: 95, Max: 99}},
+			}))),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				a := `{"self":{"id":"1","name":"abc","height":100,"gender":"male"},"match":null}`
+				return &a
+			}(),
+		},
 		{
 			name:   "person created with match",
 			person: createPerson("2", model.GenderFemale, 90, 1),
@@ -66,21 +152,733 @@ func TestAddSinglePersonAndMatch(t *testing.T) {
 			}))),
 			statusCode: http.StatusOK,
 			respBody: func() *string {
-				a := `{"self":{"id":"1","name":"abc","height":100,"gender":"male"},"match":{"id":"2","name":"","height":90,"gender":"female"}}`
-				return &a
+				a := `{"self":{"id":"1","name":"abc","height":100,"gender":"male"},"match":{"id":"2","name":"","height":90,"gender":"female"}}`
+				return &a
+			}(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var store storage.Store
+			if test.person != nil {
+				store = setupTest(t, test.person)
+			} else {
+				store = setupTest(t)
+			}
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestImportPeople(t *testing.T) {
+	defer func(generator storage.IDGenerator) { IdGenerator = generator }(IdGenerator)
+	lines := strings.Join([]string{
+		`{"name":"a","height":90,"gender":"female","number_of_wanted_dates":1}`,
+		`{"name":`,
+		``,
+		`{"name":"c","height":300,"gender":"male","number_of_wanted_dates":1}`,
+		`{"name":"d","height":100,"gender":"male","number_of_wanted_dates":1}`,
+	}, "\n")
+	tests := []struct {
+		name       string
+		req        *http.Request
+		statusCode int
+		respBody   *string
+		people     int
+	}{
+		{
+			name:       "invalid match",
+			req:        newRequest(http.MethodPost, "/people:import?match=maybe", strings.NewReader(lines)),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "line too long",
+			req:        newRequest(http.MethodPost, "/people:import", strings.NewReader(strings.Repeat(" ", maxImportLineSize+1))),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "imported",
+			req:        newRequest(http.MethodPost, "/people:import", strings.NewReader(lines)),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"imported":2,"people":[{"line":1,"id":"p1"},{"line":5,"id":"p2"}],"errors":[` +
+					`{"line":2,"error":"unexpected end of JSON input"},` +
+					`{"line":4,"error":"Key: 'Person.PersonAttributes.Height' Error:Field validation for 'Height' failed on the 'lte' tag"}]}`
+				return &s
+			}(),
+			people: 2,
+		},
+		{
+			name:       "imported and matched",
+			req:        newRequest(http.MethodPost, "/people:import?match=true", strings.NewReader(lines)),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"imported":2,"people":[{"line":1,"id":"p1","match":{"id":"p2","name":"d","height":100,"gender":"male"}},{"line":5,"id":"p2"}],"errors":[` +
+					`{"line":2,"error":"unexpected end of JSON input"},` +
+					`{"line":4,"error":"Key: 'Person.PersonAttributes.Height' Error:Field validation for 'Height' failed on the 'lte' tag"}]}`
+				return &s
+			}(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			IdGenerator = &sequenceIDGenerator{prefix: "p"}
+			store := setupTest(t)
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+			page, err := store.List(storage.PeopleFilter{}, nil, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := page.Total, test.people; got != want {
+				t.Errorf("%s got %v people but want %v", t.Name(), got, want)
+			}
+		})
+	}
+}
+
+func TestExportPeople(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		respBody    string
+	}{
+		{
+			name:        "jsonl",
+			contentType: "application/x-ndjson",
+			respBody: `{"id":"1","name":"","height":90,"gender":"female","location":{"latitude":25.03,"longitude":121.56},"number_of_wanted_dates":1,"preferences":{"seeking":["male"]},` +
+				`"matches":[{"id":"match-1","person_a":"1","person_b":"2","matched_at":"2024-05-01T10:00:00Z","remaining_dates_a":1,"remaining_dates_b":0}]}` + "\n" +
+				`{"id":"3","name":"","height":110,"gender":"male","number_of_wanted_dates":1,"matches":[]}` + "\n",
+		},
+		{
+			name:        "csv",
+			accept:      "text/csv",
+			contentType: "text/csv",
+			respBody: "id,name,height,gender,birth_date,latitude,longitude,number_of_wanted_dates,preferences,matches\n" +
+				`1,,90,female,,25.03,121.56,1,"{""seeking"":[""male""]}","[{""id"":""match-1"",""person_a"":""1"",""person_b"":""2"",""matched_at"":""2024-05-01T10:00:00Z"",""remaining_dates_a"":1,""remaining_dates_b"":0}]"` + "\n" +
+				"3,,110,male,,,,1,,[]\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			first := withLocation(createPerson("1", model.GenderFemale, 90, 2), &model.Location{Latitude: 25.03, Longitude: 121.56})
+			first.Preferences = &model.Preferences{Seeking: []model.Gender{model.GenderMale}}
+			store := setupTest(t,
+				first,
+				createPerson("2", model.GenderMale, 100, 1),
+				createPerson("3", model.GenderMale, 110, 1),
+			)
+			setupMatches(t, store, "1")
+			req := newRequest(http.MethodGet, "/people:export", nil)
+			req.Header.Set("Accept", test.accept)
+			rec := executeRequest(t, store, req)
+			if got, want := rec.Code, http.StatusOK; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if got, want := rec.Header().Get("Content-Type"), test.contentType; got != want {
+				t.Errorf("%s got content type %v but want %v", t.Name(), got, want)
+			}
+			if got, want := rec.Body.String(), test.respBody; got != want {
+				t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+			}
+		})
+	}
+}
+
+func TestGetSinglePerson(t *testing.T) {
+	tests := []struct {
+		name       string
+		person     *storage.Person
+		req        *http.Request
+		statusCode int
+		respBody   *string
+	}{
+		{
+			name:       "person not found",
+			req:        newRequest(http.MethodGet, "/person/1", nil),
+			statusCode: http.StatusNotFound,
+			respBody: func() *string {
+				s := "not found\n"
+				return &s
+			}(),
+		},
+		{
+			name: "person found",
+			person: func() *storage.Person {
+				person := withLocation(createPerson("1", model.GenderMale, 100, 3), &model.Location{Latitude: 25.03, Longitude: 121.56})
+				person.Preferences = &model.Preferences{Seeking: []model.Gender{model.GenderFemale}}
+				return person
+			}(),
+			req:        newRequest(http.MethodGet, "/person/1", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"id":"1","name":"","height":100,"gender":"male","location":{"latitude":25.03,"longitude":121.56},"number_of_wanted_dates":3,"preferences":{"seeking":["female"]}}`
+				return &s
+			}(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var store storage.Store
+			if test.person != nil {
+				store = setupTest(t, test.person)
+			} else {
+				store = setupTest(t)
+			}
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestListPeople(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *http.Request
+		statusCode int
+		respBody   *string
+	}{
+		{
+			name:       "invalid height",
+			req:        newRequest(http.MethodGet, "/people?min_height=tall", nil),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "negative dates",
+			req:        newRequest(http.MethodGet, "/people?max_dates=-1", nil),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "zero limit",
+			req:        newRequest(http.MethodGet, "/people?limit=0", nil),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "limit too large",
+			req:        newRequest(http.MethodGet, "/people?limit=101", nil),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid cursor",
+			req:        newRequest(http.MethodGet, "/people?cursor=abc", nil),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "first page",
+			req:        newRequest(http.MethodGet, "/people?limit=2", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"people":[{"id":"1","name":"","height":90,"gender":"female","number_of_wanted_dates":2},` +
+					`{"id":"2","name":"","height":100,"gender":"male","number_of_wanted_dates":1}],` +
+					`"next_cursor":"eyJoZWlnaHQiOjEwMCwiaWQiOiIyIn0","total":3}`
+				return &s
+			}(),
+		},
+		{
+			name:       "last page",
+			req:        newRequest(http.MethodGet, "/people?limit=2&cursor=eyJoZWlnaHQiOjEwMCwiaWQiOiIyIn0", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"people":[{"id":"3","name":"","height":110,"gender":"male","number_of_wanted_dates":3}],"total":3}`
+				return &s
+			}(),
+		},
+		{
+			name:       "filtered",
+			req:        newRequest(http.MethodGet, "/people?gender=male&min_dates=2", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"people":[{"id":"3","name":"","height":110,"gender":"male","number_of_wanted_dates":3}],"total":1}`
+				return &s
+			}(),
+		},
+		{
+			name:       "nobody",
+			req:        newRequest(http.MethodGet, "/people?name=Jo", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"people":[],"total":0}`
+				return &s
+			}(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := setupTest(t,
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 1),
+				createPerson("3", model.GenderMale, 110, 3),
+			)
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveSinglePerson(t *testing.T) {
+	tests := []struct {
+		name       string
+		person     *storage.Person
+		req        *http.Request
+		statusCode int
+		respBody   *string
+	}{
+		{
+			name:       "path has no id",
+			req:        newRequest(http.MethodDelete, "/person", nil),
+			statusCode: http.StatusNotFound,
+			respBody: func() *string {
+				s := "404 page not found\n"
+				return &s
+			}(),
+		},
+		{
+			name:       "person not found",
+			req:        newRequest(http.MethodDelete, "/person/1", nil),
+			statusCode: http.StatusNotFound,
+			respBody: func() *string {
+				s := "not found\n"
+				return &s
+			}(),
+		},
+		{
+			name:       "person deleted",
+			person:     createPerson("1", model.GenderMale, 1, 1),
+			req:        newRequest(http.MethodDelete, "/person/1", nil),
+			statusCode: http.StatusOK,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var store storage.Store
+			if test.person != nil {
+				store = setupTest(t, test.person)
+			} else {
+				store = setupTest(t)
+			}
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateSinglePerson(t *testing.T) {
+	tests := []struct {
+		name       string
+		person     *storage.Person
+		req        *http.Request
+		statusCode int
+		respBody   *string
+	}{
+		{
+			name:       "invalid json",
+			person:     createPerson("1", model.GenderMale, 100, 1),
+			req:        newRequest(http.MethodPatch, "/person/1", strings.NewReader(`{"height":`)),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid height",
+			person:     createPerson("1", model.GenderMale, 100, 1),
+			req:        newRequest(http.MethodPatch, "/person/1", strings.NewReader(`{"height":300}`)),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "empty name",
+			person:     createPerson("1", model.GenderMale, 100, 1),
+			req:        newRequest(http.MethodPatch, "/person/1", strings.NewReader(`{"name":""}`)),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid preferences",
+			person:     createPerson("1", model.GenderMale, 100, 1),
+			req:        newRequest(http.MethodPatch, "/person/1", strings.NewReader(`{"preferences":{"height":{"min":180,"max":150}}}`)),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "future birth date",
+			person: createPerson("1", model.GenderMale, 100, 1),
+			req: newRequest(http.MethodPatch, "/person/1",
+				strings.NewReader(`{"birth_date":"`+time.Now().AddDate(1, 0, 0).Format(model.DateLayout)+`"}`)),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "person not found",
+			req:        newRequest(http.MethodPatch, "/person/1", strings.NewReader(`{"height":120}`)),
+			statusCode: http.StatusNotFound,
+			respBody: func() *string {
+				s := "not found\n"
+				return &s
+			}(),
+		},
+		{
+			name:       "person updated",
+			person:     createPerson("1", model.GenderMale, 100, 1),
+			req:        newRequest(http.MethodPatch, "/person/1", strings.NewReader(`{"name":"Jo","height":120,"gender":"female"}`)),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"id":"1","name":"Jo","height":120,"gender":"female","number_of_wanted_dates":1}`
+				return &s
+			}(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var store storage.Store
+			if test.person != nil {
+				store = setupTest(t, test.person)
+			} else {
+				store = setupTest(t)
+			}
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchSinglePerson(t *testing.T) {
+	tests := []struct {
+		name       string
+		people     storage.People
+		matches    []string
+		req        *http.Request
+		statusCode int
+		respBody   *string
+	}{
+		{
+			name:       "person not found",
+			req:        newRequest(http.MethodPost, "/person/1/match", nil),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "no match",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 1),
+				createPerson("2", model.GenderMale, 80, 1),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/match", nil),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "match",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 1),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/match", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"id":"2","name":"","height":100,"gender":"male"}`
+				return &s
+			}(),
+		},
+		{
+			name: "evicted after matching",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 1),
+				createPerson("2", model.GenderMale, 100, 1),
+				createPerson("3", model.GenderMale, 110, 1),
+			},
+			matches:    []string{"1"},
+			req:        newRequest(http.MethodPost, "/person/1/match", nil),
+			statusCode: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := setupTest(t, test.people...)
+			setupMatches(t, store, test.matches...)
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLikePerson(t *testing.T) {
+	tests := []struct {
+		name       string
+		people     storage.People
+		matches    []string
+		likes      [][2]string
+		req        *http.Request
+		statusCode int
+		respBody   *string
+	}{
+		{
+			name:       "person not found",
+			req:        newRequest(http.MethodPost, "/person/1/like/2", nil),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "same person",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/like/1", nil),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "liked",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 1),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/like/2", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"match":null}`
+				return &s
+			}(),
+		},
+		{
+			name: "liked each other",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 1),
+			},
+			likes:      [][2]string{{"2", "1"}},
+			req:        newRequest(http.MethodPost, "/person/1/like/2", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"match":{"id":"match-1","person_a":"1","person_b":"2","matched_at":"2024-05-01T10:00:00Z","remaining_dates_a":1,"remaining_dates_b":0}}`
+				return &s
+			}(),
+		},
+		{
+			name: "already matched",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 2),
+			},
+			matches:    []string{"1"},
+			req:        newRequest(http.MethodPost, "/person/1/like/2", nil),
+			statusCode: http.StatusConflict,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := setupTest(t, test.people...)
+			setupMatches(t, store, test.matches...)
+			for _, like := range test.likes {
+				if _, err := store.Like(like[0], like[1]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPassPerson(t *testing.T) {
+	tests := []struct {
+		name       string
+		people     storage.People
+		req        *http.Request
+		statusCode int
+		respBody   *string
+	}{
+		{
+			name: "target not found",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/pass/2", nil),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "passed",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 1),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/pass/2", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := "passed"
+				return &s
+			}(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := setupTest(t, test.people...)
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBlockPerson(t *testing.T) {
+	tests := []struct {
+		name       string
+		people     storage.People
+		req        *http.Request
+		statusCode int
+		respBody   *string
+	}{
+		{
+			name: "target not found",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/block/2", nil),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "block oneself",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/block/1", nil),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "blocked",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 1),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/block/2", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := "blocked"
+				return &s
+			}(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := setupTest(t, test.people...)
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReportPerson(t *testing.T) {
+	tests := []struct {
+		name       string
+		people     storage.People
+		req        *http.Request
+		statusCode int
+		respBody   *string
+	}{
+		{
+			name: "no json body",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 1),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/report/2", nil),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "unknown reason",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 1),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/report/2", bytes.NewReader(jsonMarshal(t, ReportRequest{Reason: "rude"}))),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "target not found",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/report/2", bytes.NewReader(jsonMarshal(t, ReportRequest{Reason: storage.ReasonSpam}))),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "reported",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 1),
+			},
+			req:        newRequest(http.MethodPost, "/person/1/report/2", bytes.NewReader(jsonMarshal(t, ReportRequest{Reason: storage.ReasonSpam, Comment: "ads"}))),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"id":"match-1","reporter":"1","target":"2","reason":"spam","comment":"ads","reported_at":"2024-05-01T10:00:00Z"}`
+				return &s
 			}(),
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			var teardown func(tb testing.TB)
-			if test.person != nil {
-				teardown = setupTest(t, test.person)
-			} else {
-				teardown = setupTest(t)
-			}
-			defer teardown(t)
-			rec := executeRequest(t, test.req)
+			store := setupTest(t, test.people...)
+			rec := executeRequest(t, store, test.req)
 			if got, want := rec.Code, test.statusCode; got != want {
 				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
 			}
@@ -93,49 +891,51 @@ func TestAddSinglePersonAndMatch(t *testing.T) {
 	}
 }
 
-func TestRemoveSinglePerson(t *testing.T) {
+func TestQueryReports(t *testing.T) {
 	tests := []struct {
 		name       string
-		person     *storage.Person
 		req        *http.Request
 		statusCode int
 		respBody   *string
 	}{
 		{
-			name:       "path has no id",
-			req:        newRequest(http.MethodDelete, "/person", nil),
-			statusCode: http.StatusNotFound,
-			respBody: func() *string {
-				s := "404 page not found\n"
-				return &s
-			}(),
+			name:       "unknown reason",
+			req:        newRequest(http.MethodGet, "/admin/reports?reason=rude", nil),
+			statusCode: http.StatusBadRequest,
 		},
 		{
-			name:       "person not found",
-			req:        newRequest(http.MethodDelete, "/person/1", nil),
-			statusCode: http.StatusNotFound,
+			name:       "no reports",
+			req:        newRequest(http.MethodGet, "/admin/reports?target=1", nil),
+			statusCode: http.StatusOK,
 			respBody: func() *string {
-				s := "not found\n"
+				s := `{"reports":[]}`
 				return &s
 			}(),
 		},
 		{
-			name:       "person deleted",
-			person:     createPerson("1", model.GenderMale, 1, 1),
-			req:        newRequest(http.MethodDelete, "/person/1", nil),
+			name:       "reports by reason",
+			req:        newRequest(http.MethodGet, "/admin/reports?reason=harassment", nil),
 			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"reports":[{"id":"match-1","reporter":"3","target":"2","reason":"harassment","reported_at":"2024-05-01T10:00:00Z"}]}`
+				return &s
+			}(),
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			var teardown func(tb testing.TB)
-			if test.person != nil {
-				teardown = setupTest(t, test.person)
-			} else {
-				teardown = setupTest(t)
+			store := setupTest(t,
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 1),
+				createPerson("3", model.GenderFemale, 95, 1),
+			)
+			if _, err := store.Report("1", "2", storage.ReasonSpam, ""); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.Report("3", "2", storage.ReasonHarassment, ""); err != nil {
+				t.Fatal(err)
 			}
-			defer teardown(t)
-			rec := executeRequest(t, test.req)
+			rec := executeRequest(t, store, test.req)
 			if got, want := rec.Code, test.statusCode; got != want {
 				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
 			}
@@ -192,7 +992,21 @@ func TestAddSinglePeople(t *testing.T) {
 			req:        newRequest(http.MethodGet, "/person/1/matches?n=1", nil),
 			statusCode: http.StatusOK,
 			respBody: func() *string {
-				s := `{"matches":[{"id":"2","name":"","height":100,"gender":"male"}]}`
+				s := `{"matches":[{"id":"2","name":"","height":100,"gender":"male","score":0.5919698602928606}]}`
+				return &s
+			}(),
+		},
+		{
+			name: "match with distance",
+			people: storage.People{
+				withLocation(createPerson("1", model.GenderFemale, 90, 1), &model.Location{Latitude: 25, Longitude: 121.5}),
+				withLocation(createPerson("2", model.GenderMale, 100, 1), &model.Location{Latitude: 25, Longitude: 121.5}),
+				createPerson("3", model.GenderMale, 110, 1),
+			},
+			req:        newRequest(http.MethodGet, "/person/1/matches?n=2", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"matches":[{"id":"2","name":"","height":100,"gender":"male","distance_km":0,"score":0.8419698602928606},{"id":"3","name":"","height":110,"gender":"male","score":0.5338338208091532}]}`
 				return &s
 			}(),
 		},
@@ -208,21 +1022,361 @@ func TestAddSinglePeople(t *testing.T) {
 			req:        newRequest(http.MethodGet, "/person/1/matches?n=3", nil),
 			statusCode: http.StatusOK,
 			respBody: func() *string {
-				s := `{"matches":[{"id":"2","name":"","height":100,"gender":"male"},{"id":"3","name":"","height":100,"gender":"male"},{"id":"4","name":"","height":100,"gender":"male"}]}`
+				s := `{"matches":[{"id":"2","name":"","height":100,"gender":"male","score":0.5919698602928606},{"id":"3","name":"","height":100,"gender":"male","score":0.5919698602928606},{"id":"4","name":"","height":100,"gender":"male","score":0.5919698602928606}],"next_cursor":"eyJoZWlnaHQiOjEwMCwiaWQiOiI0Iiwic2NvcmUiOjAuNTkxOTY5ODYwMjkyODYwNn0"}`
+				return &s
+			}(),
+		},
+		{
+			name: "match next page",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 1),
+				createPerson("2", model.GenderMale, 100, 1),
+				createPerson("3", model.GenderMale, 100, 1),
+				createPerson("4", model.GenderMale, 100, 1),
+				createPerson("5", model.GenderMale, 100, 1),
+			},
+			req:        newRequest(http.MethodGet, "/person/1/matches?n=3&cursor=eyJoZWlnaHQiOjEwMCwiaWQiOiI0Iiwic2NvcmUiOjAuNTkxOTY5ODYwMjkyODYwNn0", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"matches":[{"id":"5","name":"","height":100,"gender":"male","score":0.5919698602928606}]}`
+				return &s
+			}(),
+		},
+		{
+			name: "invalid cursor",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 1),
+				createPerson("2", model.GenderMale, 100, 1),
+			},
+			req:        newRequest(http.MethodGet, "/person/1/matches?n=3&cursor=!", nil),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "cursor of another order",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 1),
+				createPerson("2", model.GenderMale, 100, 1),
+			},
+			req:        newRequest(http.MethodGet, "/person/1/matches?n=3&cursor=eyJoZWlnaHQiOjEwMCwiaWQiOiI0In0", nil),
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := setupTest(t, test.people...)
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryMatchHistory(t *testing.T) {
+	tests := []struct {
+		name       string
+		people     storage.People
+		matches    []string
+		req        *http.Request
+		statusCode int
+		respBody   *string
+	}{
+		{
+			name:       "person not found",
+			req:        newRequest(http.MethodGet, "/person/1/history", nil),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "no history",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 1),
+			},
+			req:        newRequest(http.MethodGet, "/person/1/history", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"matches":[]}`
+				return &s
+			}(),
+		},
+		{
+			name: "history of evicted person",
+			people: storage.People{
+				createPerson("1", model.GenderFemale, 90, 1),
+				createPerson("2", model.GenderMale, 100, 2),
+			},
+			matches:    []string{"1"},
+			req:        newRequest(http.MethodGet, "/person/1/history", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"matches":[{"id":"match-1","person_a":"1","person_b":"2","matched_at":"2024-05-01T10:00:00Z","remaining_dates_a":0,"remaining_dates_b":1}]}`
 				return &s
 			}(),
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			var teardown func(tb testing.TB)
-			if len(test.people) > 0 {
-				teardown = setupTest(t, test.people...)
-			} else {
-				teardown = setupTest(t)
+			store := setupTest(t, test.people...)
+			setupMatches(t, store, test.matches...)
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *http.Request
+		statusCode int
+		respBody   *string
+	}{
+		{
+			name:       "match not found",
+			req:        newRequest(http.MethodGet, "/matches/not-found", nil),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "match",
+			req:        newRequest(http.MethodGet, "/matches/match-1", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"id":"match-1","person_a":"1","person_b":"2","matched_at":"2024-05-01T10:00:00Z","remaining_dates_a":0,"remaining_dates_b":1}`
+				return &s
+			}(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := setupTest(t,
+				createPerson("1", model.GenderFemale, 90, 1),
+				createPerson("2", model.GenderMale, 100, 2),
+			)
+			setupMatches(t, store, "1")
+			rec := executeRequest(t, store, test.req)
+			if got, want := rec.Code, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.respBody != nil {
+				if got, want := rec.Body.String(), *test.respBody; got != want {
+					t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		mutate     func(store storage.Store) error
+		statusCode int
+		respBody   string
+	}{
+		{
+			name:       "person not found",
+			id:         "3",
+			statusCode: http.StatusNotFound,
+			respBody:   "not found\n",
+		},
+		{
+			name: "match and eviction",
+			id:   "2",
+			mutate: func(store storage.Store) error {
+				_, err := store.Match("1")
+				return err
+			},
+			statusCode: http.StatusOK,
+			respBody: "event: match\n" +
+				`data: {"type":"match","person_id":"2","match":{"id":"match-1","person_a":"1","person_b":"2","matched_at":"2024-05-01T10:00:00Z","remaining_dates_a":1,"remaining_dates_b":0},"at":"2024-05-01T10:00:00Z"}` + "\n\n" +
+				"event: eviction\n" +
+				`data: {"type":"eviction","person_id":"2","match":{"id":"match-1","person_a":"1","person_b":"2","matched_at":"2024-05-01T10:00:00Z","remaining_dates_a":1,"remaining_dates_b":0},"at":"2024-05-01T10:00:00Z"}` + "\n\n",
+		},
+		{
+			name: "removal",
+			id:   "1",
+			mutate: func(store storage.Store) error {
+				return store.Remove("1")
+			},
+			statusCode: http.StatusOK,
+			respBody: "event: removal\n" +
+				`data: {"type":"removal","person_id":"1","at":"2024-05-01T10:00:00Z"}` + "\n\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := setupTest(t,
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 1),
+			)
+			server := httptest.NewServer(NewRouter(store))
+			defer server.Close()
+			// the response is received once the person is subscribed.
+			resp, err := http.Get(server.URL + "/person/" + test.id + "/events")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if got, want := resp.StatusCode, test.statusCode; got != want {
+				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
+			}
+			if test.mutate != nil {
+				if err := test.mutate(store); err != nil {
+					t.Fatal(err)
+				}
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := string(body), test.respBody; got != want {
+				t.Errorf("%s got response body %v but want %v", t.Name(), got, want)
+			}
+		})
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer receiver.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	match := `{"id":"match-1","person_a":"1","person_b":"2","matched_at":"2024-05-01T10:00:00Z","remaining_dates_a":1,"remaining_dates_b":0}`
+	tests := []struct {
+		name       string
+		subscribe  []string
+		events     []storage.EventType
+		req        *http.Request
+		statusCode int
+		respBody   *string
+	}{
+		{
+			name:       "invalid url",
+			req:        newRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"url":"localhost","events":["match"],"secret":"s"}`)),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown event",
+			req:        newRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"url":"http://localhost","events":["like"],"secret":"s"}`)),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "no secret",
+			req:        newRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"url":"http://localhost","events":["match"]}`)),
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "create",
+			req:        newRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"url":"http://localhost/hook","events":["match","eviction"],"secret":"s"}`)),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"id":"hook-1","url":"http://localhost/hook","events":["match","eviction"],"created_at":"2024-05-01T10:00:00Z"}`
+				return &s
+			}(),
+		},
+		{
+			name:       "list",
+			subscribe:  []string{receiver.URL},
+			req:        newRequest(http.MethodGet, "/webhooks", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"webhooks":[{"id":"hook-1","url":"` + receiver.URL + `","events":["match"],"created_at":"2024-05-01T10:00:00Z"}]}`
+				return &s
+			}(),
+		},
+		{
+			name:       "remove",
+			subscribe:  []string{receiver.URL},
+			req:        newRequest(http.MethodDelete, "/webhooks/hook-1", nil),
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "remove unknown",
+			req:        newRequest(http.MethodDelete, "/webhooks/hook-1", nil),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "deliveries of unknown",
+			req:        newRequest(http.MethodGet, "/webhooks/hook-1/deliveries", nil),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "deliveries",
+			subscribe:  []string{receiver.URL},
+			req:        newRequest(http.MethodGet, "/webhooks/hook-1/deliveries", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"deliveries":[` +
+					`{"id":"hook-1","subscription_id":"hook-1","event":{"type":"match","person_id":"1","match":` + match + `,"at":"2024-05-01T10:00:00Z"},"status":"delivered","attempts":[{"at":"2024-05-01T10:00:00Z","status_code":200}]},` +
+					`{"id":"hook-1","subscription_id":"hook-1","event":{"type":"match","person_id":"2","match":` + match + `,"at":"2024-05-01T10:00:00Z"},"status":"delivered","attempts":[{"at":"2024-05-01T10:00:00Z","status_code":200}]}]}`
+				return &s
+			}(),
+		},
+		{
+			name:       "dead letters",
+			subscribe:  []string{failing.URL},
+			events:     []storage.EventType{storage.EventEvicted},
+			req:        newRequest(http.MethodGet, "/webhooks/dead-letters", nil),
+			statusCode: http.StatusOK,
+			respBody: func() *string {
+				s := `{"deliveries":[` +
+					`{"id":"hook-1","subscription_id":"hook-1","event":{"type":"eviction","person_id":"2","match":` + match + `,"at":"2024-05-01T10:00:00Z"},"status":"dead","attempts":[{"at":"2024-05-01T10:00:00Z","status_code":500,"error":"unexpected status 500 Internal Server Error"}]}]}`
+				return &s
+			}(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := func() time.Time { return time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC) }
+			hooks := webhook.NewDispatcher(
+				webhook.WithIDGenerator(storage.FakeIDGenerator{FakeID: "hook-1"}),
+				webhook.WithClock(now),
+				webhook.WithRetries(1, 0),
+			)
+			bus := storage.NewEventBus()
+			unsubscribe := bus.Subscribe(0, hooks.Handle)
+			store := storage.NewMemoryStore(
+				storage.WithIDGenerator(storage.FakeIDGenerator{FakeID: "match-1"}),
+				storage.WithClock(now),
+				storage.WithEventBus(bus),
+			)
+			for _, person := range []*storage.Person{
+				createPerson("1", model.GenderFemale, 90, 2),
+				createPerson("2", model.GenderMale, 100, 1),
+			} {
+				if _, err := store.Add(person.ID, &person.Person); err != nil {
+					t.Fatal(err)
+				}
+			}
+			events := []storage.EventType{storage.EventMatched}
+			if test.events != nil {
+				events = test.events
+			}
+			for _, url := range test.subscribe {
+				hooks.Subscribe(url, events, "secret")
+			}
+			if len(test.subscribe) > 0 {
+				setupMatches(t, store, "1")
 			}
-			defer teardown(t)
-			rec := executeRequest(t, test.req)
+			// the deliveries are done once the events are handled and the dispatcher is closed.
+			unsubscribe()
+			hooks.Close()
+			rec := httptest.NewRecorder()
+			NewRouter(store, WithWebhooks(hooks)).ServeHTTP(rec, test.req)
 			if got, want := rec.Code, test.statusCode; got != want {
 				t.Errorf("%s got status code %v but want %v", t.Name(), got, want)
 			}
@@ -235,9 +1389,9 @@ func TestAddSinglePeople(t *testing.T) {
 	}
 }
 
-func executeRequest(t *testing.T, req *http.Request) *httptest.ResponseRecorder {
+func executeRequest(t *testing.T, store storage.Store, req *http.Request) *httptest.ResponseRecorder {
 	t.Helper()
-	router := NewRouter()
+	router := NewRouter(store)
 	responseRecorder := httptest.NewRecorder()
 	router.ServeHTTP(responseRecorder, req)
 	return responseRecorder
@@ -257,17 +1411,18 @@ func jsonMarshal(t *testing.T, v interface{}) []byte {
 	return bytes
 }
 
-func setupTest(tb testing.TB, people ...*storage.Person) func(tb testing.TB) {
-	setupPeople(tb, people...)
-	return func(tb testing.TB) {
-		storage.ClearAll()
-	}
-}
-func setupPeople(tb testing.TB, people ...*storage.Person) {
+func setupTest(tb testing.TB, people ...*storage.Person) storage.Store {
 	tb.Helper()
+	store := storage.NewMemoryStore(
+		storage.WithIDGenerator(storage.FakeIDGenerator{FakeID: "match-1"}),
+		storage.WithClock(func() time.Time { return time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC) }),
+	)
 	for _, person := range people {
-		storage.Add(person.ID, &person.Person)
+		if _, err := store.Add(person.ID, &person.Person); err != nil {
+			tb.Fatal(err)
+		}
 	}
+	return store
 }
 
 func createPerson(id string, gender model.Gender, height int, numDates int) *storage.Person {
@@ -277,3 +1432,27 @@ func createPerson(id string, gender model.Gender, height int, numDates int) *sto
 		NumberOfWantedDates: numDates,
 	}}
 }
+
+func withLocation(person *storage.Person, location *model.Location) *storage.Person {
+	person.Location = location
+	return person
+}
+
+func setupMatches(tb testing.TB, store storage.Store, ids ...string) {
+	tb.Helper()
+	for _, id := range ids {
+		if _, err := store.Match(id); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+// sequenceIDGenerator generates the ids prefix1, prefix2 and so on.
+type sequenceIDGenerator struct {
+	prefix string
+	next   atomic.Int64
+}
+
+func (g *sequenceIDGenerator) GenerateKey() string {
+	return g.prefix + strconv.FormatInt(g.next.Add(1), 10)
+}
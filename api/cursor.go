@@ -0,0 +1,31 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// encodeCursor returns the opaque token of the position of a page, clients
+// pass it back as is to get the next page.
+func encodeCursor(cursor any) (string, error) {
+	bytes, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// decodeCursor decodes the token returned by encodeCursor into cursor.
+func decodeCursor(token string, cursor any) error {
+	bytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return errInvalidCursor
+	}
+	if err := json.Unmarshal(bytes, cursor); err != nil {
+		return errInvalidCursor
+	}
+	return nil
+}
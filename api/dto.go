@@ -1,17 +1,134 @@
 package api
 
-import "github.com/bito_interview/model"
+import (
+	"github.com/bito_interview/model"
+	"github.com/bito_interview/storage"
+	"github.com/bito_interview/webhook"
+)
 
 type AddAndMatchResponse struct {
 	Self  *PersonResponse `json:"self"`
 	Match *PersonResponse `json:"match"`
 }
 
+// PersonResponse is the public attributes of a person, which leave out
+// his/her location.
 type PersonResponse struct {
 	ID string `json:"id"`
 	model.PersonAttributes
+	// DistanceKm and Score are the distance from and the compatibility score
+	// with the person whose possible matches are queried.
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+	Score      *float64 `json:"score,omitempty"`
 }
 
+// personResponseOf returns the public attributes of the person, the
+// location is left out so that only the distance to the person is told.
+func personResponseOf(person *storage.Person) *PersonResponse {
+	attributes := person.PersonAttributes
+	attributes.Location = nil
+	return &PersonResponse{ID: person.ID, PersonAttributes: attributes}
+}
+
+// ProfileResponse is the whole profile of a person, including the dates
+// he/she still wants and his/her preferences.
+type ProfileResponse struct {
+	ID string `json:"id"`
+	model.Person
+}
+
+// PeoplePage is a page of people, NextCursor is left out on the last page.
+type PeoplePage struct {
+	People     []ProfileResponse `json:"people"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	Total      int               `json:"total"`
+}
+
+// ExportedPerson is a line of the export, the whole profile of the person
+// along with his/her matches.
+type ExportedPerson struct {
+	ProfileResponse
+	Matches []MatchResponse `json:"matches"`
+}
+
+// ImportResponse reports the people imported and the lines rejected, lines
+// are numbered from 1.
+type ImportResponse struct {
+	Imported int              `json:"imported"`
+	People   []ImportedPerson `json:"people"`
+	Errors   []LineError      `json:"errors"`
+}
+
+// ImportedPerson is the person imported from the line, Match is the partner
+// found when matching is requested.
+type ImportedPerson struct {
+	Line  int             `json:"line"`
+	ID    string          `json:"id"`
+	Match *PersonResponse `json:"match,omitempty"`
+}
+
+type LineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// PossibleMatches is a page of possible matches, NextCursor is left out on the last page.
 type PossibleMatches struct {
-	Matches []PersonResponse `json:"matches"`
+	Matches    []PersonResponse `json:"matches"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+type MatchResponse struct {
+	storage.MatchRecord
+}
+
+// LikeResponse carries the match made when both people liked each other.
+type LikeResponse struct {
+	Match *MatchResponse `json:"match"`
+}
+
+type MatchHistory struct {
+	Matches []MatchResponse `json:"matches"`
+}
+
+type ReportRequest struct {
+	Reason  storage.ReportReason `json:"reason" validate:"required,oneof=spam harassment fake_profile inappropriate underage other"`
+	Comment string               `json:"comment" validate:"max=1000"`
+}
+
+type ReportResponse struct {
+	storage.Report
+}
+
+type Reports struct {
+	Reports []ReportResponse `json:"reports"`
+}
+
+// EventResponse is the data of a server-sent event of the person.
+type EventResponse struct {
+	storage.Event
+}
+
+// WebhookRequest subscribes the URL to the events of the given types, whose
+// payloads are signed with the secret.
+type WebhookRequest struct {
+	URL    string              `json:"url" validate:"required,http_url"`
+	Events []storage.EventType `json:"events" validate:"required,min=1,unique,dive,oneof=match removal eviction"`
+	Secret string              `json:"secret" validate:"required"`
+}
+
+type WebhookResponse struct {
+	webhook.Subscription
+}
+
+type Webhooks struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+type DeliveryResponse struct {
+	webhook.Delivery
+}
+
+type Deliveries struct {
+	Deliveries []DeliveryResponse `json:"deliveries"`
 }
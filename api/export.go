@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/bito_interview/storage"
+)
+
+// exporter writes the people exported one at a time in a format.
+type exporter interface {
+	write(person *storage.Person, history []storage.MatchRecord) error
+	// flush writes whatever is buffered, it is called once every person is written.
+	flush() error
+}
+
+func exportedPerson(person *storage.Person, history []storage.MatchRecord) ExportedPerson {
+	exported := ExportedPerson{
+		ProfileResponse: ProfileResponse{ID: person.ID, Person: person.Person},
+		Matches:         []MatchResponse{},
+	}
+	for _, match := range history {
+		exported.Matches = append(exported.Matches, MatchResponse{MatchRecord: match})
+	}
+	return exported
+}
+
+// jsonlExporter writes every person as a line of JSON.
+type jsonlExporter struct {
+	encoder *json.Encoder
+}
+
+func newJSONLExporter(w io.Writer) *jsonlExporter {
+	return &jsonlExporter{encoder: json.NewEncoder(w)}
+}
+
+func (e *jsonlExporter) write(person *storage.Person, history []storage.MatchRecord) error {
+	return e.encoder.Encode(exportedPerson(person, history))
+}
+
+func (e *jsonlExporter) flush() error {
+	return nil
+}
+
+// csvHeader are the columns of the CSV export, the preferences and the
+// matches are JSON encoded.
+var csvHeader = []string{"id", "name", "height", "gender", "birth_date", "latitude", "longitude",
+	"number_of_wanted_dates", "preferences", "matches"}
+
+// csvExporter writes every person as a CSV record.
+type csvExporter struct {
+	writer *csv.Writer
+}
+
+// newCSVExporter returns the exporter once the header is written.
+func newCSVExporter(w io.Writer) (*csvExporter, error) {
+	e := &csvExporter{writer: csv.NewWriter(w)}
+	if err := e.writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *csvExporter) write(person *storage.Person, history []storage.MatchRecord) error {
+	exported := exportedPerson(person, history)
+	var latitude, longitude, preferences string
+	if location := person.Location; location != nil {
+		latitude = strconv.FormatFloat(location.Latitude, 'f', -1, 64)
+		longitude = strconv.FormatFloat(location.Longitude, 'f', -1, 64)
+	}
+	if person.Preferences != nil {
+		bytes, err := json.Marshal(person.Preferences)
+		if err != nil {
+			return err
+		}
+		preferences = string(bytes)
+	}
+	matches, err := json.Marshal(exported.Matches)
+	if err != nil {
+		return err
+	}
+	return e.writer.Write([]string{person.ID, person.Name, strconv.Itoa(person.Height), string(person.Gender),
+		person.BirthDate, latitude, longitude, strconv.Itoa(person.NumberOfWantedDates), preferences, string(matches)})
+}
+
+func (e *csvExporter) flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
@@ -1,13 +1,85 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/bito_interview/api"
+	"github.com/bito_interview/rpc"
+	"github.com/bito_interview/storage"
+	"github.com/bito_interview/webhook"
 )
 
+// webhookBuffer is the number of domain events queued for the webhooks
+// before the store waits for them.
+const webhookBuffer = 1024
+
 func main() {
+	backend := flag.String("store", "memory", "candidate pool backend: memory, wal or sqlite")
+	dataDir := flag.String("data-dir", "data", "directory persisting the candidate pool of the wal and sqlite backends")
+	snapshotEvery := flag.Int("snapshot-every", storage.DefaultSnapshotEvery, "number of logged mutations between two snapshots of the wal backend")
+	rulesFile := flag.String("rules", "", "JSON file of the matching rules, females match taller males and males match shorter females when empty")
+	strategy := flag.String("strategy", "first", "strategy picking the partner of a match: first, closest-height, highest-score, round-robin or random")
+	grpcAddr := flag.String("grpc-addr", ":9090", "address the gRPC server is listening to")
+	seed := flag.Int64("seed", 0, "seed of the random strategy, the current time when 0")
+	flag.Parse()
+
+	var opts []storage.Option
+	if *rulesFile != "" {
+		rules, err := storage.LoadRules(*rulesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, storage.WithRules(rules))
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	matchStrategy, err := storage.ParseMatchStrategy(*strategy, *seed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts = append(opts, storage.WithMatchStrategy(matchStrategy))
+	// the webhooks are told about every event of the pool.
+	bus := storage.NewEventBus()
+	hooks := webhook.NewDispatcher()
+	bus.Subscribe(webhookBuffer, hooks.Handle)
+	opts = append(opts, storage.WithEventBus(bus))
+	store, err := newStore(*backend, *dataDir, *snapshotEvery, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", *grpcAddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go func() {
+		log.Println("gRPC listen to", *grpcAddr)
+		log.Fatal(rpc.NewServer(store).Serve(lis))
+	}()
+
 	log.Println("listen to :8080")
-	log.Fatal(http.ListenAndServe(":8080", api.NewRouter()))
+	log.Fatal(http.ListenAndServe(":8080", api.NewRouter(store, api.WithWebhooks(hooks))))
+}
+
+func newStore(backend string, dataDir string, snapshotEvery int, opts ...storage.Option) (storage.Store, error) {
+	switch backend {
+	case "memory":
+		return storage.NewMemoryStore(opts...), nil
+	case "wal":
+		return storage.NewDurableStore(dataDir, snapshotEvery, opts...)
+	case "sqlite":
+		if err := os.MkdirAll(dataDir, 0o755); err != nil {
+			return nil, err
+		}
+		return storage.NewSQLiteStore(filepath.Join(dataDir, "pool.db"), opts...)
+	}
+	return nil, fmt.Errorf("unknown store %q", backend)
 }
@@ -1,23 +1,176 @@
 package model
 
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Gender is any lowercase value, the well known ones are listed below.
 type Gender string
 
 const (
-	GenderFemale Gender = "female"
-	GenderMale   Gender = "male"
+	GenderFemale    Gender = "female"
+	GenderMale      Gender = "male"
+	GenderNonBinary Gender = "non-binary"
 )
 
+// DateLayout is the layout of dates such as the birth date.
+const DateLayout = "2006-01-02"
+
 type PersonAttributes struct {
-	Name   string `json:"name" validate:"required"`
-	Height int    `json:"height" validate:"gt=0,lte=250"`
-	Gender Gender `json:"gender" validate:"oneof=female male"`
+	Name      string    `json:"name" validate:"required"`
+	Height    int       `json:"height" validate:"gt=0,lte=250"`
+	Gender    Gender    `json:"gender" validate:"required,max=32,printascii,lowercase"`
+	BirthDate string    `json:"birth_date,omitempty" validate:"omitempty,datetime=2006-01-02,notfuture"`
+	Location  *Location `json:"location,omitempty"`
+}
+
+// Location is a geographic coordinate in degrees.
+type Location struct {
+	Latitude  float64 `json:"latitude" validate:"gte=-90,lte=90"`
+	Longitude float64 `json:"longitude" validate:"gte=-180,lte=180"`
+}
+
+// EarthRadiusKm is the mean radius of the earth.
+const EarthRadiusKm = 6371.0
+
+// DistanceKm returns the great-circle distance between two locations.
+func DistanceKm(a *Location, b *Location) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Birth returns the birth date, false when it is unknown.
+func (a *PersonAttributes) Birth() (time.Time, bool) {
+	if a.BirthDate == "" {
+		return time.Time{}, false
+	}
+	birth, err := time.Parse(DateLayout, a.BirthDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return birth, true
+}
+
+// Age returns the age at the given time, false when the birth date is unknown.
+func (a *PersonAttributes) Age(now time.Time) (int, bool) {
+	birth, ok := a.Birth()
+	if !ok {
+		return 0, false
+	}
+	return AgeAt(birth, now), true
+}
+
+// AgeAt returns the age at now of a person born at birth.
+func AgeAt(birth time.Time, now time.Time) int {
+	age := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		age--
+	}
+	return age
+}
+
+// HeightRange is an inclusive range of heights.
+type HeightRange struct {
+	Min int `json:"min" validate:"gt=0,lte=250"`
+	Max int `json:"max" validate:"gtefield=Min,lte=250"`
+}
+
+// AgeRange is an inclusive range of ages.
+type AgeRange struct {
+	Min int `json:"min" validate:"gte=0,lte=150"`
+	Max int `json:"max" validate:"gtefield=Min,lte=150"`
+}
+
+// Preferences are what a person wants from a partner, every preference left empty accepts anyone.
+type Preferences struct {
+	Height        *HeightRange `json:"height,omitempty"`
+	Age           *AgeRange    `json:"age,omitempty"`
+	MaxDistanceKm *float64     `json:"max_distance_km,omitempty" validate:"omitempty,gt=0"`
+	Seeking       []Gender     `json:"seeking,omitempty" validate:"omitempty,unique,dive,required,max=32,printascii,lowercase"`
 }
 
 type Person struct {
 	PersonAttributes
-	NumberOfWantedDates int `json:"number_of_wanted_dates" validate:"gt=0"`
+	NumberOfWantedDates int          `json:"number_of_wanted_dates" validate:"gt=0"`
+	Preferences         *Preferences `json:"preferences,omitempty"`
+}
+
+// PersonUpdate is a partial update of a person, every field left nil keeps
+// the current value. The preferences given replace the current ones as a whole.
+type PersonUpdate struct {
+	Name                *string      `json:"name,omitempty" validate:"omitnil,min=1"`
+	Height              *int         `json:"height,omitempty" validate:"omitnil,gt=0,lte=250"`
+	Gender              *Gender      `json:"gender,omitempty" validate:"omitnil,min=1,max=32,printascii,lowercase"`
+	BirthDate           *string      `json:"birth_date,omitempty" validate:"omitnil,datetime=2006-01-02,notfuture"`
+	Location            *Location    `json:"location,omitempty"`
+	NumberOfWantedDates *int         `json:"number_of_wanted_dates,omitempty" validate:"omitnil,gt=0"`
+	Preferences         *Preferences `json:"preferences,omitempty"`
+}
+
+// Apply sets the fields of the update on the person.
+func (u *PersonUpdate) Apply(p *Person) {
+	if u.Name != nil {
+		p.Name = *u.Name
+	}
+	if u.Height != nil {
+		p.Height = *u.Height
+	}
+	if u.Gender != nil {
+		p.Gender = *u.Gender
+	}
+	if u.BirthDate != nil {
+		p.BirthDate = *u.BirthDate
+	}
+	if u.Location != nil {
+		location := *u.Location
+		p.Location = &location
+	}
+	if u.NumberOfWantedDates != nil {
+		p.NumberOfWantedDates = *u.NumberOfWantedDates
+	}
+	if u.Preferences != nil {
+		preferences := *u.Preferences
+		p.Preferences = &preferences
+	}
 }
 
 func (p *Person) DecreaseDateCount() {
 	p.NumberOfWantedDates--
 }
+
+// Accepts reports whether the other person satisfies the preferences of the
+// person at the given time. A preferred age range rejects people whose age is
+// unknown and a maximum distance rejects anyone when either location is unknown.
+func (p *Person) Accepts(other *PersonAttributes, now time.Time) bool {
+	if p.Preferences == nil {
+		return true
+	}
+	if height := p.Preferences.Height; height != nil && (other.Height < height.Min || other.Height > height.Max) {
+		return false
+	}
+	if ageRange := p.Preferences.Age; ageRange != nil {
+		age, ok := other.Age(now)
+		if !ok || age < ageRange.Min || age > ageRange.Max {
+			return false
+		}
+	}
+	if maxDistance := p.Preferences.MaxDistanceKm; maxDistance != nil {
+		if p.Location == nil || other.Location == nil || DistanceKm(p.Location, other.Location) > *maxDistance {
+			return false
+		}
+	}
+	return p.Seeks(other.Gender)
+}
+
+// Seeks reports whether the person wants a partner of the gender.
+func (p *Person) Seeks(gender Gender) bool {
+	if p.Preferences == nil || len(p.Preferences.Seeking) == 0 {
+		return true
+	}
+	return slices.Contains(p.Preferences.Seeking, gender)
+}
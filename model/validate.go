@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// NewValidator returns a validator of the people and their updates, which
+// knows the notfuture tag rejecting the dates after today.
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterValidation("notfuture", notFuture)
+	return validate
+}
+
+// notFuture accepts the dates up to today, a malformed date is left to the datetime tag.
+func notFuture(fl validator.FieldLevel) bool {
+	date, err := time.Parse(DateLayout, fl.Field().String())
+	if err != nil {
+		return true
+	}
+	today, _ := time.Parse(DateLayout, time.Now().Format(DateLayout))
+	return !date.After(today)
+}
@@ -0,0 +1,86 @@
+package rpc
+
+import (
+	"github.com/bito_interview/model"
+	pb "github.com/bito_interview/rpc/matchingpb"
+	"github.com/bito_interview/storage"
+)
+
+// personOf converts the person of a request to the model, leaving out the
+// location and the preferences which are unset.
+func personOf(p *pb.Person) *model.Person {
+	person := &model.Person{
+		PersonAttributes: model.PersonAttributes{
+			Name:      p.Name,
+			Height:    int(p.Height),
+			Gender:    model.Gender(p.Gender),
+			BirthDate: p.BirthDate,
+			Location:  locationOf(p.Location),
+		},
+		NumberOfWantedDates: int(p.NumberOfWantedDates),
+	}
+	if prefs := p.Preferences; prefs != nil {
+		person.Preferences = &model.Preferences{MaxDistanceKm: prefs.MaxDistanceKm}
+		if prefs.Height != nil {
+			person.Preferences.Height = &model.HeightRange{Min: int(prefs.Height.Min), Max: int(prefs.Height.Max)}
+		}
+		if prefs.Age != nil {
+			person.Preferences.Age = &model.AgeRange{Min: int(prefs.Age.Min), Max: int(prefs.Age.Max)}
+		}
+		for _, gender := range prefs.Seeking {
+			person.Preferences.Seeking = append(person.Preferences.Seeking, model.Gender(gender))
+		}
+	}
+	return person
+}
+
+func locationOf(l *pb.Location) *model.Location {
+	if l == nil {
+		return nil
+	}
+	return &model.Location{Latitude: l.Latitude, Longitude: l.Longitude}
+}
+
+func pbLocationOf(l *model.Location) *pb.Location {
+	if l == nil {
+		return nil
+	}
+	return &pb.Location{Latitude: l.Latitude, Longitude: l.Longitude}
+}
+
+// candidateOf converts the public attributes of the person, the location is
+// left out so that only the distance to the person is told.
+func candidateOf(p *storage.Person) *pb.Candidate {
+	return &pb.Candidate{
+		Id:        p.ID,
+		Name:      p.Name,
+		Height:    int32(p.Height),
+		Gender:    string(p.Gender),
+		BirthDate: p.BirthDate,
+	}
+}
+
+// profileOf converts the whole profile of the person.
+func profileOf(p *storage.Person) *pb.Person {
+	person := &pb.Person{
+		Name:                p.Name,
+		Height:              int32(p.Height),
+		Gender:              string(p.Gender),
+		BirthDate:           p.BirthDate,
+		Location:            pbLocationOf(p.Location),
+		NumberOfWantedDates: int32(p.NumberOfWantedDates),
+	}
+	if prefs := p.Preferences; prefs != nil {
+		person.Preferences = &pb.Preferences{MaxDistanceKm: prefs.MaxDistanceKm}
+		if prefs.Height != nil {
+			person.Preferences.Height = &pb.HeightRange{Min: int32(prefs.Height.Min), Max: int32(prefs.Height.Max)}
+		}
+		if prefs.Age != nil {
+			person.Preferences.Age = &pb.AgeRange{Min: int32(prefs.Age.Min), Max: int32(prefs.Age.Max)}
+		}
+		for _, gender := range prefs.Seeking {
+			person.Preferences.Seeking = append(person.Preferences.Seeking, string(gender))
+		}
+	}
+	return person
+}
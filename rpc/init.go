@@ -0,0 +1,9 @@
+package rpc
+
+import "github.com/bito_interview/storage"
+
+var IdGenerator storage.IDGenerator
+
+func init() {
+	IdGenerator = storage.UUIDGenerator{}
+}
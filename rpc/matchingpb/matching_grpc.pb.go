@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: matchingpb/matching.proto
+
+package matchingpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	MatchingService_AddAndMatch_FullMethodName          = "/matching.v1.MatchingService/AddAndMatch"
+	MatchingService_RemovePerson_FullMethodName         = "/matching.v1.MatchingService/RemovePerson"
+	MatchingService_QueryPossibleMatches_FullMethodName = "/matching.v1.MatchingService/QueryPossibleMatches"
+	MatchingService_GetPerson_FullMethodName            = "/matching.v1.MatchingService/GetPerson"
+)
+
+// MatchingServiceClient is the client API for MatchingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MatchingServiceClient interface {
+	// AddAndMatch adds the person to the pool and matches him/her with a
+	// possible match when there is one.
+	AddAndMatch(ctx context.Context, in *AddAndMatchRequest, opts ...grpc.CallOption) (*AddAndMatchResponse, error)
+	// RemovePerson removes the person from the pool.
+	RemovePerson(ctx context.Context, in *RemovePersonRequest, opts ...grpc.CallOption) (*RemovePersonResponse, error)
+	// QueryPossibleMatches streams at most n possible matches of the person in
+	// the order of the matching rules.
+	QueryPossibleMatches(ctx context.Context, in *QueryPossibleMatchesRequest, opts ...grpc.CallOption) (MatchingService_QueryPossibleMatchesClient, error)
+	// GetPerson returns the whole profile of the person.
+	GetPerson(ctx context.Context, in *GetPersonRequest, opts ...grpc.CallOption) (*Profile, error)
+}
+
+type matchingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMatchingServiceClient(cc grpc.ClientConnInterface) MatchingServiceClient {
+	return &matchingServiceClient{cc}
+}
+
+func (c *matchingServiceClient) AddAndMatch(ctx context.Context, in *AddAndMatchRequest, opts ...grpc.CallOption) (*AddAndMatchResponse, error) {
+	out := new(AddAndMatchResponse)
+	err := c.cc.Invoke(ctx, MatchingService_AddAndMatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchingServiceClient) RemovePerson(ctx context.Context, in *RemovePersonRequest, opts ...grpc.CallOption) (*RemovePersonResponse, error) {
+	out := new(RemovePersonResponse)
+	err := c.cc.Invoke(ctx, MatchingService_RemovePerson_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchingServiceClient) QueryPossibleMatches(ctx context.Context, in *QueryPossibleMatchesRequest, opts ...grpc.CallOption) (MatchingService_QueryPossibleMatchesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MatchingService_ServiceDesc.Streams[0], MatchingService_QueryPossibleMatches_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &matchingServiceQueryPossibleMatchesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MatchingService_QueryPossibleMatchesClient interface {
+	Recv() (*Candidate, error)
+	grpc.ClientStream
+}
+
+type matchingServiceQueryPossibleMatchesClient struct {
+	grpc.ClientStream
+}
+
+func (x *matchingServiceQueryPossibleMatchesClient) Recv() (*Candidate, error) {
+	m := new(Candidate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *matchingServiceClient) GetPerson(ctx context.Context, in *GetPersonRequest, opts ...grpc.CallOption) (*Profile, error) {
+	out := new(Profile)
+	err := c.cc.Invoke(ctx, MatchingService_GetPerson_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MatchingServiceServer is the server API for MatchingService service.
+// All implementations must embed UnimplementedMatchingServiceServer
+// for forward compatibility
+type MatchingServiceServer interface {
+	// AddAndMatch adds the person to the pool and matches him/her with a
+	// possible match when there is one.
+	AddAndMatch(context.Context, *AddAndMatchRequest) (*AddAndMatchResponse, error)
+	// RemovePerson removes the person from the pool.
+	RemovePerson(context.Context, *RemovePersonRequest) (*RemovePersonResponse, error)
+	// QueryPossibleMatches streams at most n possible matches of the person in
+	// the order of the matching rules.
+	QueryPossibleMatches(*QueryPossibleMatchesRequest, MatchingService_QueryPossibleMatchesServer) error
+	// GetPerson returns the whole profile of the person.
+	GetPerson(context.Context, *GetPersonRequest) (*Profile, error)
+	mustEmbedUnimplementedMatchingServiceServer()
+}
+
+// UnimplementedMatchingServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMatchingServiceServer struct {
+}
+
+func (UnimplementedMatchingServiceServer) AddAndMatch(context.Context, *AddAndMatchRequest) (*AddAndMatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddAndMatch not implemented")
+}
+func (UnimplementedMatchingServiceServer) RemovePerson(context.Context, *RemovePersonRequest) (*RemovePersonResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemovePerson not implemented")
+}
+func (UnimplementedMatchingServiceServer) QueryPossibleMatches(*QueryPossibleMatchesRequest, MatchingService_QueryPossibleMatchesServer) error {
+	return status.Errorf(codes.Unimplemented, "method QueryPossibleMatches not implemented")
+}
+func (UnimplementedMatchingServiceServer) GetPerson(context.Context, *GetPersonRequest) (*Profile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPerson not implemented")
+}
+func (UnimplementedMatchingServiceServer) mustEmbedUnimplementedMatchingServiceServer() {}
+
+// UnsafeMatchingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MatchingServiceServer will
+// result in compilation errors.
+type UnsafeMatchingServiceServer interface {
+	mustEmbedUnimplementedMatchingServiceServer()
+}
+
+func RegisterMatchingServiceServer(s grpc.ServiceRegistrar, srv MatchingServiceServer) {
+	s.RegisterService(&MatchingService_ServiceDesc, srv)
+}
+
+func _MatchingService_AddAndMatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddAndMatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchingServiceServer).AddAndMatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchingService_AddAndMatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchingServiceServer).AddAndMatch(ctx, req.(*AddAndMatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchingService_RemovePerson_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePersonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchingServiceServer).RemovePerson(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchingService_RemovePerson_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchingServiceServer).RemovePerson(ctx, req.(*RemovePersonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchingService_QueryPossibleMatches_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryPossibleMatchesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MatchingServiceServer).QueryPossibleMatches(m, &matchingServiceQueryPossibleMatchesServer{stream})
+}
+
+type MatchingService_QueryPossibleMatchesServer interface {
+	Send(*Candidate) error
+	grpc.ServerStream
+}
+
+type matchingServiceQueryPossibleMatchesServer struct {
+	grpc.ServerStream
+}
+
+func (x *matchingServiceQueryPossibleMatchesServer) Send(m *Candidate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MatchingService_GetPerson_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPersonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchingServiceServer).GetPerson(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchingService_GetPerson_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchingServiceServer).GetPerson(ctx, req.(*GetPersonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MatchingService_ServiceDesc is the grpc.ServiceDesc for MatchingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MatchingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "matching.v1.MatchingService",
+	HandlerType: (*MatchingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddAndMatch",
+			Handler:    _MatchingService_AddAndMatch_Handler,
+		},
+		{
+			MethodName: "RemovePerson",
+			Handler:    _MatchingService_RemovePerson_Handler,
+		},
+		{
+			MethodName: "GetPerson",
+			Handler:    _MatchingService_GetPerson_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "QueryPossibleMatches",
+			Handler:       _MatchingService_QueryPossibleMatches_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "matchingpb/matching.proto",
+}
@@ -0,0 +1,99 @@
+// Package rpc serves the candidate pool over gRPC, mirroring the HTTP API of
+// the api package on the same storage layer.
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative matchingpb/matching.proto
+
+import (
+	"context"
+
+	"github.com/bito_interview/model"
+	pb "github.com/bito_interview/rpc/matchingpb"
+	"github.com/bito_interview/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var validate = model.NewValidator()
+
+type server struct {
+	pb.UnimplementedMatchingServiceServer
+	store storage.Store
+}
+
+// NewServer returns the gRPC server serving the candidate pool of the given store.
+func NewServer(store storage.Store, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(opts...)
+	pb.RegisterMatchingServiceServer(s, &server{store: store})
+	return s
+}
+
+// statusOf maps the errors of the store to the gRPC status codes.
+func statusOf(err error) error {
+	switch err {
+	case storage.ErrNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case storage.ErrCursorOrder:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func (s *server) AddAndMatch(ctx context.Context, req *pb.AddAndMatchRequest) (*pb.AddAndMatchResponse, error) {
+	if req.Person == nil {
+		return nil, status.Error(codes.InvalidArgument, "person is required")
+	}
+	newPerson := personOf(req.Person)
+	if err := validate.Struct(newPerson); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	storagePerson, err := s.store.Add(IdGenerator.GenerateKey(), newPerson)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	resp := &pb.AddAndMatchResponse{Self: candidateOf(storagePerson)}
+	if match, err := s.store.Match(storagePerson.ID); err == nil {
+		resp.Match = candidateOf(match)
+	}
+	return resp, nil
+}
+
+func (s *server) RemovePerson(ctx context.Context, req *pb.RemovePersonRequest) (*pb.RemovePersonResponse, error) {
+	if err := s.store.Remove(req.Id); err != nil {
+		return nil, statusOf(err)
+	}
+	return &pb.RemovePersonResponse{}, nil
+}
+
+func (s *server) QueryPossibleMatches(req *pb.QueryPossibleMatchesRequest, stream pb.MatchingService_QueryPossibleMatchesServer) error {
+	if req.N <= 0 {
+		return status.Error(codes.InvalidArgument, "n positive number is expected")
+	}
+	var after *storage.Cursor
+	if req.After != nil {
+		after = &storage.Cursor{Height: int(req.After.Height), ID: req.After.Id, Score: req.After.Score}
+	}
+	matches, _, err := s.store.PossibleMatches(req.Id, after, int(req.N))
+	if err != nil {
+		return statusOf(err)
+	}
+	for _, match := range matches {
+		candidate := candidateOf(match.Person)
+		candidate.DistanceKm = match.DistanceKm
+		score := match.Score
+		candidate.Score = &score
+		if err := stream.Send(candidate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *server) GetPerson(ctx context.Context, req *pb.GetPersonRequest) (*pb.Profile, error) {
+	person, err := s.store.Get(req.Id)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return &pb.Profile{Id: person.ID, Person: profileOf(person)}, nil
+}
@@ -0,0 +1,270 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bito_interview/model"
+	pb "github.com/bito_interview/rpc/matchingpb"
+	"github.com/bito_interview/storage"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestAddAndMatch(t *testing.T) {
+	IdGenerator = storage.FakeIDGenerator{
+		FakeID: "1",
+	}
+	tests := []struct {
+		name   string
+		person *storage.Person
+		req    *pb.AddAndMatchRequest
+		code   codes.Code
+		resp   *pb.AddAndMatchResponse
+	}{
+		{
+			name: "no person",
+			req:  &pb.AddAndMatchRequest{},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "invalid person",
+			req:  &pb.AddAndMatchRequest{Person: &pb.Person{Name: "abc", Height: 100, Gender: "Non Binary", NumberOfWantedDates: 1}},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "person created no match",
+			req:  &pb.AddAndMatchRequest{Person: &pb.Person{Name: "abc", Height: 100, Gender: "male", NumberOfWantedDates: 10}},
+			code: codes.OK,
+			resp: &pb.AddAndMatchResponse{Self: &pb.Candidate{Id: "1", Name: "abc", Height: 100, Gender: "male"}},
+		},
+		{
+			name:   "person created with match",
+			person: createPerson("2", model.GenderFemale, 90, 1),
+			req: &pb.AddAndMatchRequest{Person: &pb.Person{
+				Name: "abc", Height: 100, Gender: "male", NumberOfWantedDates: 10,
+				Location:    &pb.Location{Latitude: 25.03, Longitude: 121.56},
+				Preferences: &pb.Preferences{Seeking: []string{"female"}},
+			}},
+			code: codes.OK,
+			resp: &pb.AddAndMatchResponse{
+				Self:  &pb.Candidate{Id: "1", Name: "abc", Height: 100, Gender: "male"},
+				Match: &pb.Candidate{Id: "2", Height: 90, Gender: "female"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var people []*storage.Person
+			if test.person != nil {
+				people = append(people, test.person)
+			}
+			client := setupTest(t, people...)
+			resp, err := client.AddAndMatch(context.Background(), test.req)
+			if got, want := status.Code(err), test.code; got != want {
+				t.Fatalf("%s got %v but want: %v", t.Name(), got, want)
+			}
+			if diff := cmp.Diff(resp, test.resp, protocmp.Transform()); diff != "" {
+				t.Errorf("%s got want:\n%s", t.Name(), diff)
+			}
+		})
+	}
+}
+
+func TestRemovePerson(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		code codes.Code
+	}{
+		{
+			name: "removed",
+			id:   "1",
+			code: codes.OK,
+		},
+		{
+			name: "not found",
+			id:   "2",
+			code: codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := setupTest(t, createPerson("1", model.GenderMale, 100, 1))
+			_, err := client.RemovePerson(context.Background(), &pb.RemovePersonRequest{Id: test.id})
+			if got, want := status.Code(err), test.code; got != want {
+				t.Fatalf("%s got %v but want: %v", t.Name(), got, want)
+			}
+			if _, err := client.GetPerson(context.Background(), &pb.GetPersonRequest{Id: test.id}); status.Code(err) != codes.NotFound {
+				t.Errorf("%s got %v but want: %v", t.Name(), status.Code(err), codes.NotFound)
+			}
+		})
+	}
+}
+
+func TestQueryPossibleMatches(t *testing.T) {
+	score := func(v float64) *float64 {
+		return &v
+	}
+	tests := []struct {
+		name string
+		req  *pb.QueryPossibleMatchesRequest
+		code codes.Code
+		want []*pb.Candidate
+	}{
+		{
+			name: "invalid n",
+			req:  &pb.QueryPossibleMatchesRequest{Id: "1"},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "not found",
+			req:  &pb.QueryPossibleMatchesRequest{Id: "4", N: 1},
+			code: codes.NotFound,
+		},
+		{
+			name: "stream at most n",
+			req:  &pb.QueryPossibleMatchesRequest{Id: "1", N: 2},
+			code: codes.OK,
+			want: []*pb.Candidate{
+				{Id: "2", Height: 110, Gender: "male", Score: score(0.5919698602928606)},
+				{Id: "3", Height: 120, Gender: "male", Score: score(0.5338338208091532)},
+			},
+		},
+		{
+			name: "stream after cursor",
+			req:  &pb.QueryPossibleMatchesRequest{Id: "1", N: 2, After: &pb.Cursor{Height: 110, Id: "2", Score: score(0.5919698602928606)}},
+			code: codes.OK,
+			want: []*pb.Candidate{
+				{Id: "3", Height: 120, Gender: "male", Score: score(0.5338338208091532)},
+			},
+		},
+		{
+			name: "cursor without score",
+			req:  &pb.QueryPossibleMatchesRequest{Id: "1", N: 2, After: &pb.Cursor{Height: 110, Id: "2"}},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := setupTest(t,
+				createPerson("1", model.GenderFemale, 100, 1),
+				createPerson("2", model.GenderMale, 110, 1),
+				createPerson("3", model.GenderMale, 120, 1),
+			)
+			stream, err := client.QueryPossibleMatches(context.Background(), test.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []*pb.Candidate
+			for {
+				candidate, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					if code := status.Code(err); code != test.code {
+						t.Fatalf("%s got %v but want: %v", t.Name(), code, test.code)
+					}
+					break
+				}
+				got = append(got, candidate)
+			}
+			if diff := cmp.Diff(got, test.want, protocmp.Transform()); diff != "" {
+				t.Errorf("%s got want:\n%s", t.Name(), diff)
+			}
+		})
+	}
+}
+
+func TestGetPerson(t *testing.T) {
+	person := createPerson("1", model.GenderFemale, 100, 2)
+	person.Name = "abc"
+	person.BirthDate = "1990-01-31"
+	person.Preferences = &model.Preferences{
+		Height:  &model.HeightRange{Min: 100, Max: 200},
+		Seeking: []model.Gender{model.GenderMale},
+	}
+	tests := []struct {
+		name string
+		id   string
+		code codes.Code
+		resp *pb.Profile
+	}{
+		{
+			name: "found",
+			id:   "1",
+			code: codes.OK,
+			resp: &pb.Profile{Id: "1", Person: &pb.Person{
+				Name: "abc", Height: 100, Gender: "female", BirthDate: "1990-01-31", NumberOfWantedDates: 2,
+				Preferences: &pb.Preferences{Height: &pb.HeightRange{Min: 100, Max: 200}, Seeking: []string{"male"}},
+			}},
+		},
+		{
+			name: "not found",
+			id:   "2",
+			code: codes.NotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := setupTest(t, person)
+			resp, err := client.GetPerson(context.Background(), &pb.GetPersonRequest{Id: test.id})
+			if got, want := status.Code(err), test.code; got != want {
+				t.Fatalf("%s got %v but want: %v", t.Name(), got, want)
+			}
+			if diff := cmp.Diff(resp, test.resp, protocmp.Transform()); diff != "" {
+				t.Errorf("%s got want:\n%s", t.Name(), diff)
+			}
+		})
+	}
+}
+
+// setupTest serves a memory store of the people on an in-process listener
+// and returns the client connected to it.
+func setupTest(tb testing.TB, people ...*storage.Person) pb.MatchingServiceClient {
+	tb.Helper()
+	store := storage.NewMemoryStore(
+		storage.WithIDGenerator(storage.FakeIDGenerator{FakeID: "match-1"}),
+		storage.WithClock(func() time.Time { return time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC) }),
+	)
+	for _, person := range people {
+		if _, err := store.Add(person.ID, &person.Person); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	lis := bufconn.Listen(1 << 20)
+	server := NewServer(store)
+	go server.Serve(lis)
+	tb.Cleanup(server.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { conn.Close() })
+	return pb.NewMatchingServiceClient(conn)
+}
+
+func createPerson(id string, gender model.Gender, height int, numDates int) *storage.Person {
+	return &storage.Person{ID: id, Person: model.Person{
+		PersonAttributes:    model.PersonAttributes{Height: height, Gender: gender},
+		NumberOfWantedDates: numDates,
+	}}
+}
@@ -5,6 +5,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/bito_interview/model"
 )
@@ -13,14 +14,30 @@ var (
 	ErrNotFound = errors.New("not found")
 )
 
-var (
-	peopleByGender map[model.Gender]People
-	All            personById
-	rwMutex        *sync.RWMutex
-)
+// MemoryStore keeps the candidate pool in memory. People are indexed by id
+// and by gender, where the index of a gender buckets people by birth year
+// and sorts every bucket by height. The index of a gender is created when
+// the first person of the gender is added. People whose location is known
+// are also indexed by the cell of the location grid.
+type MemoryStore struct {
+	peopleByGender map[model.Gender]*genderIndex
+	locations      locationGrid
+	all            personById
+	// activeAt is the last time every person was added or matched.
+	activeAt map[string]time.Time
+	ledger   *matchLedger
+	swipes   *swipes
+	blocks   *blocks
+	// reports are in the order they were made.
+	reports []*Report
+	rwMutex *sync.RWMutex
+	options
+}
+
+var _ Store = (*MemoryStore)(nil)
 
 type Person struct {
-	ID string
+	ID string `json:"id"`
 	model.Person
 }
 type People []*Person
@@ -48,27 +65,58 @@ func remove(people People, person *Person) People {
 	return slices.Delete(people, index, index+1)
 }
 
-func queryNMales(person *Person, n int) (People, error) {
-	index, _ := slices.BinarySearchFunc(peopleByGender[model.GenderMale], &Person{Person: model.Person{PersonAttributes: model.PersonAttributes{Height: person.Height + 1}}}, heightCmp)
-	if index >= len(peopleByGender[model.GenderMale]) {
-		return nil, ErrNotFound
+// queryN returns at most n candidates allowed by the rules in the order of
+// the rules, skipping the partners the person was already matched to, the
+// people the person passed on and those blocked by or blocking the person. The
+// person and the candidates must accept each other's preferences. The
+// candidates of a person bounding the distance are looked up in the location
+// grid rather than in the gender indexes. Only the candidates after the
+// cursor are looked up and the cursor of the next page is returned.
+func (s *MemoryStore) queryN(person *Person, after *Cursor, n int) (Candidates, *Cursor) {
+	now := s.now()
+	var ranges []People
+	rules := map[model.Gender]Rule{}
+	searches := s.rules.searches(person)
+	for _, rule := range searches {
+		rules[rule.CandidateGender] = rule
 	}
-	remain := min(len(peopleByGender[model.GenderMale])-index, n)
-	return peopleByGender[model.GenderMale][index : index+remain], nil
-}
-
-func queryNFemales(person *Person, n int) (People, error) {
-	index, _ := slices.BinarySearchFunc(peopleByGender[model.GenderFemale], &Person{Person: model.Person{PersonAttributes: model.PersonAttributes{Height: person.Height}}}, heightCmp)
-	return peopleByGender[model.GenderFemale][:min(index, n)], nil
+	if center, radiusKm, ok := maxDistance(person); ok {
+		near := s.locations.near(center, radiusKm)
+		slices.SortFunc(near, heightCmp)
+		ranges = append(ranges, near)
+	} else {
+		for _, rule := range searches {
+			ranges = append(ranges, rule.candidates(person, s.peopleByGender[rule.CandidateGender], now)...)
+		}
+	}
+	// the candidates ranked by score are only cut at the cursor once scored.
+	if s.rules.Order != OrderScore {
+		for i, people := range ranges {
+			ranges[i] = after.trim(people, s.rules.Order)
+		}
+	}
+	matches := People{}
+	limit := s.rules.limit(n)
+	walk(ranges, s.rules.Order, func(candidate *Person) bool {
+		rule, ok := rules[candidate.Gender]
+		if ok && rule.Allows(person, candidate, now) && !s.ledger.matched(person.ID, candidate.ID) &&
+			!s.swipes.passed(person.ID, candidate.ID) && !s.blocks.between(person.ID, candidate.ID) &&
+			s.rules.mutuallyAccepted(person, candidate, now) {
+			matches = append(matches, candidate)
+		}
+		return len(matches) < limit
+	})
+	activeAt := func(id string) time.Time {
+		return s.activeAt[id]
+	}
+	return s.rules.candidatesOf(person, matches, after, n, activeAt, now)
 }
 
-func queryN(person *Person, n int) (People, error) {
-	if person.Gender == model.GenderFemale {
-		return queryNMales(person, n)
-	} else if person.Gender == model.GenderMale {
-		return queryNFemales(person, n)
-	}
-	return nil, ErrNotFound
+// mutuallyAccepted reports whether the preferences of both people accept
+// each other, and whether both of them seek the gender of the other one.
+func (r Rules) mutuallyAccepted(person *Person, candidate *Person, now time.Time) bool {
+	return person.Accepts(&candidate.PersonAttributes, now) && candidate.Accepts(&person.PersonAttributes, now) &&
+		r.seeks(person, candidate.Gender) && r.seeks(candidate, person.Gender)
 }
 
 type personById map[string]*Person
@@ -94,85 +142,221 @@ func (s personById) removePerson(id string) error {
 	return ErrNotFound
 }
 
-func Add(id string, person *model.Person) *Person {
-	rwMutex.Lock()
-	defer rwMutex.Unlock()
-	newPerson := All.addPersonWithId(id, person)
-	peopleByGender[newPerson.Gender] = insert(peopleByGender[newPerson.Gender], newPerson)
+func (s *MemoryStore) Add(id string, person *model.Person) (*Person, error) {
+	defer s.flush()
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	return s.add(id, person, s.now()), nil
+}
+
+func (s *MemoryStore) Import(people People) error {
+	defer s.flush()
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	s.importPeople(people, s.now())
+	return nil
+}
+
+func (s *MemoryStore) importPeople(people People, at time.Time) {
+	for _, person := range people {
+		s.add(person.ID, &person.Person, at)
+	}
+}
+
+func (s *MemoryStore) add(id string, person *model.Person, at time.Time) *Person {
+	newPerson := s.all.addPersonWithId(id, person)
+	s.activeAt[id] = at
+	s.index(newPerson)
+	s.publish(PersonAdded{Person: *newPerson, At: at.UTC()})
 	return newPerson
 }
 
-func Remove(id string) error {
-	rwMutex.Lock()
-	defer rwMutex.Unlock()
-	person, err := All.getPerson(id)
+// index inserts the person in the index of his/her gender and in the location grid.
+func (s *MemoryStore) index(person *Person) {
+	index, ok := s.peopleByGender[person.Gender]
+	if !ok {
+		index = newGenderIndex()
+		s.peopleByGender[person.Gender] = index
+	}
+	index.insert(person)
+	s.locations.insert(person)
+}
+
+// unindex removes the person from the index of his/her gender and from the location grid.
+func (s *MemoryStore) unindex(person *Person) {
+	if index, ok := s.peopleByGender[person.Gender]; ok {
+		index.remove(person)
+	}
+	s.locations.remove(person)
+}
+
+func (s *MemoryStore) Get(id string) (*Person, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	person, err := s.all.getPerson(id)
+	if err != nil {
+		return nil, err
+	}
+	copied := *person
+	return &copied, nil
+}
+
+func (s *MemoryStore) Remove(id string) error {
+	defer s.flush()
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	person, err := s.all.getPerson(id)
 	if err == nil {
-		All.removePerson(id)
-		peopleByGender[person.Gender] = remove(peopleByGender[person.Gender], person)
+		s.remove(person)
+		s.publish(PersonRemoved{PersonID: id, At: s.now().UTC()})
 	}
 	return err
 }
 
-func Match(id string) (*Person, error) {
-	rwMutex.Lock()
-	defer rwMutex.Unlock()
-	person, err := All.getPerson(id)
+func (s *MemoryStore) remove(person *Person) {
+	s.all.removePerson(person.ID)
+	delete(s.activeAt, person.ID)
+	s.swipes.forget(person.ID)
+	s.blocks.forget(person.ID)
+	s.unindex(person)
+}
+
+func (s *MemoryStore) Update(id string, update *model.PersonUpdate) (*Person, error) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	person, err := s.all.getPerson(id)
 	if err != nil {
 		return nil, err
 	}
-	possible, err := possibleMatches(id, 1)
+	updated := person.Person
+	update.Apply(&updated)
+	copied := *s.update(person, &updated)
+	return &copied, nil
+}
+
+// update replaces the person with the updated one, taken out of the indexes
+// first so that a new gender, height, birth date or location puts him/her
+// back at the right position. The person is replaced rather than changed in
+// place since the people returned by the other methods are read after the
+// lock is released.
+func (s *MemoryStore) update(person *Person, updated *model.Person) *Person {
+	s.unindex(person)
+	newPerson := s.all.addPersonWithId(person.ID, updated)
+	s.index(newPerson)
+	return newPerson
+}
+
+func (s *MemoryStore) Match(id string) (*Person, error) {
+	defer s.flush()
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	person, match, err := s.findMatch(id)
 	if err != nil {
 		return nil, err
 	}
+	s.match(person, match, s.newMatchRecord(person, match))
+	return match, nil
+}
+
+// findMatch looks up the person and the possible match picked by the
+// strategy without changing the pool.
+func (s *MemoryStore) findMatch(id string) (*Person, *Person, error) {
+	person, err := s.all.getPerson(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	possible, _, err := s.possibleMatches(id, nil, candidateLimit(s.strategy))
+	if err != nil {
+		return nil, nil, err
+	}
 	if len(possible) == 0 {
-		return nil, ErrNotFound
+		return nil, nil, ErrNotFound
+	}
+	return person, s.strategy.Pick(person, possible), nil
+}
+
+// newMatchRecord returns the ledger record of matching person with match.
+func (s *MemoryStore) newMatchRecord(person *Person, match *Person) *MatchRecord {
+	return &MatchRecord{
+		ID:              s.idGenerator.GenerateKey(),
+		PersonA:         person.ID,
+		PersonB:         match.ID,
+		MatchedAt:       s.now().UTC(),
+		RemainingDatesA: person.NumberOfWantedDates - 1,
+		RemainingDatesB: match.NumberOfWantedDates - 1,
 	}
+}
 
-	match := possible[0]
+// match decreases the wanted dates of both people, evicts those who have no
+// dates left, records the match in the ledger and publishes the events of
+// the match.
+func (s *MemoryStore) match(person *Person, match *Person, rec *MatchRecord) {
+	s.ledger.add(rec)
+	s.swipes.forgetPair(person.ID, match.ID)
+	s.activeAt[person.ID], s.activeAt[match.ID] = rec.MatchedAt, rec.MatchedAt
 	person.DecreaseDateCount()
 	match.DecreaseDateCount()
 	if person.NumberOfWantedDates <= 0 {
-		peopleByGender[person.Gender] = remove(peopleByGender[person.Gender], person)
-		All.removePerson(person.ID)
+		s.remove(person)
 	}
 	if match.NumberOfWantedDates <= 0 {
-		peopleByGender[match.Gender] = remove(peopleByGender[match.Gender], match)
-		All.removePerson(match.ID)
+		s.remove(match)
 	}
-	return match, nil
+	s.publish(matchedEvents(rec)...)
 }
 
-func PossibleMatches(id string, maxNum int) (People, error) {
-	rwMutex.RLock()
-	defer rwMutex.RUnlock()
-	return possibleMatches(id, maxNum)
+func (s *MemoryStore) PossibleMatches(id string, after *Cursor, maxNum int) (Candidates, *Cursor, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	return s.possibleMatches(id, after, maxNum)
 }
 
-func possibleMatches(id string, maxNum int) (People, error) {
+func (s *MemoryStore) possibleMatches(id string, after *Cursor, maxNum int) (Candidates, *Cursor, error) {
 	if maxNum <= 0 {
-		return nil, ErrNotFound
+		return nil, nil, ErrNotFound
+	}
+	if err := after.checkOrder(s.rules.Order); err != nil {
+		return nil, nil, err
 	}
-	person, err := All.getPerson(id)
+	person, err := s.all.getPerson(id)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if person.NumberOfWantedDates == 0 {
-		return nil, ErrNotFound
-	}
-	matches, err := queryN(person, maxNum)
-	if err != nil {
-		return nil, err
+		return nil, nil, ErrNotFound
 	}
+	matches, next := s.queryN(person, after, maxNum)
 	if len(matches) == 0 {
-		return nil, ErrNotFound
+		return nil, nil, ErrNotFound
 	}
-	return matches, nil
+	return matches, next, nil
+}
+
+func (s *MemoryStore) History(id string) ([]MatchRecord, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	history := s.ledger.history(id)
+	if len(history) == 0 {
+		if _, err := s.all.getPerson(id); err != nil {
+			return nil, err
+		}
+	}
+	return history, nil
+}
+
+func (s *MemoryStore) GetMatch(matchID string) (*MatchRecord, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	return s.ledger.get(matchID)
+}
+
+func (s *MemoryStore) Subscribe(personID string) (<-chan Event, func()) {
+	return s.notifier.Subscribe(personID)
 }
 
-// ClearAll reset the storage.
-func ClearAll() {
-	peopleByGender = map[model.Gender]People{}
-	peopleByGender[model.GenderFemale] = People{}
-	peopleByGender[model.GenderMale] = People{}
-	All = personById{}
+// Close closes the channels of the subscribers and stops following the
+// event bus. The store must not be used afterwards.
+func (s *MemoryStore) Close() error {
+	s.notifier.close()
+	return nil
 }
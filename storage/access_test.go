@@ -1,7 +1,10 @@
 package storage
 
 import (
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/bito_interview/model"
 	"github.com/google/go-cmp/cmp"
@@ -37,18 +40,98 @@ func TestAdd(t *testing.T) {
 		},
 	}
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			teardown := setupTest(t)
-			defer teardown(t)
-			got := Add(test.ID, test.person)
-			if diff := cmp.Diff(got, test.addedPerson); diff != "" {
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend)
+				got, err := s.Add(test.ID, test.person)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(got, test.addedPerson); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+			})
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			t.Parallel()
+			s := setupTest(t, backend, createPerson("1", model.GenderFemale, 10, 2))
+			imported := People{
+				createPerson("2", model.GenderMale, 12, 1),
+				createPerson("3", model.GenderMale, 14, 1),
+				createPerson("4", model.GenderFemale, 8, 1),
+			}
+			if err := s.Import(imported); err != nil {
+				t.Fatal(err)
+			}
+			counts := backend.count(t, s)
+			if got, want := counts[model.GenderMale]+counts[model.GenderFemale], 4; got != want {
+				t.Errorf("%s got %v people but want: %v", t.Name(), got, want)
+			}
+			got, _, err := s.PossibleMatches("1", nil, 5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got.People(), imported[:2]); diff != "" {
 				t.Errorf("%s got want:\n%s", t.Name(), diff)
 			}
 		})
 	}
 }
 
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		err    error
+		person *Person
+	}{
+		{
+			name: "not_found",
+			id:   "5",
+			err:  ErrNotFound,
+		},
+		{
+			name:   "matched_once",
+			id:     "1",
+			person: createPerson("1", model.GenderFemale, 10, 1),
+		},
+		{
+			name: "evicted",
+			id:   "2",
+			err:  ErrNotFound,
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend,
+					createPerson("1", model.GenderFemale, 10, 2),
+					createPerson("2", model.GenderMale, 12, 1),
+				)
+				if _, err := s.Match("1"); err != nil {
+					t.Fatal(err)
+				}
+				got, err := s.Get(test.id)
+				if got, want := err, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+				if diff := cmp.Diff(got, test.person); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+			})
+		}
+	}
+}
+
 func TestRemove(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -75,26 +158,162 @@ func TestRemove(t *testing.T) {
 		},
 	}
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			teardown := setupTest(
-				t,
-				createPerson("id-1", model.GenderMale, 10, 1),
-				createPerson("id-2", model.GenderFemale, 10, 1),
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(
+					t,
+					backend,
+					createPerson("id-1", model.GenderMale, 10, 1),
+					createPerson("id-2", model.GenderFemale, 10, 1),
+				)
+				if got, want := s.Remove(test.id), test.expectedErr; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+				counts := backend.count(t, s)
+				if got, want := counts[model.GenderMale]+counts[model.GenderFemale], test.numPeople; got != want {
+					t.Errorf("%s got %v people but want: %v", t.Name(), got, want)
+				}
+				if got, want := counts[model.GenderMale], test.numMales; got != want {
+					t.Errorf("%s got %v males but want: %v", t.Name(), got, want)
+				}
+				if got, want := counts[model.GenderFemale], test.numFemales; got != want {
+					t.Errorf("%s got %v females but want: %v", t.Name(), got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	height := func(height int) *int { return &height }
+	gender := func(gender model.Gender) *model.Gender { return &gender }
+	tests := []struct {
+		name     string
+		id       string
+		update   *model.PersonUpdate
+		err      error
+		updated  *Person
+		possible []string
+	}{
+		{
+			name:     "not_found",
+			id:       "5",
+			update:   &model.PersonUpdate{Height: height(20)},
+			err:      ErrNotFound,
+			possible: []string{"2", "3"},
+		},
+		{
+			name:     "taller",
+			id:       "2",
+			update:   &model.PersonUpdate{Height: height(16)},
+			updated:  createPerson("2", model.GenderMale, 16, 1),
+			possible: []string{"3", "2"},
+		},
+		{
+			name:     "shorter",
+			id:       "2",
+			update:   &model.PersonUpdate{Height: height(8)},
+			updated:  createPerson("2", model.GenderMale, 8, 1),
+			possible: []string{"3"},
+		},
+		{
+			name:     "gender",
+			id:       "2",
+			update:   &model.PersonUpdate{Gender: gender(model.GenderFemale)},
+			updated:  createPerson("2", model.GenderFemale, 12, 1),
+			possible: []string{"3"},
+		},
+		{
+			name:     "seeker",
+			id:       "1",
+			update:   &model.PersonUpdate{Height: height(13)},
+			updated:  createPerson("1", model.GenderFemale, 13, 2),
+			possible: []string{"3"},
+		},
+		{
+			name:     "nothing",
+			id:       "2",
+			update:   &model.PersonUpdate{},
+			updated:  createPerson("2", model.GenderMale, 12, 1),
+			possible: []string{"2", "3"},
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend,
+					createPerson("1", model.GenderFemale, 10, 2),
+					createPerson("2", model.GenderMale, 12, 1),
+					createPerson("3", model.GenderMale, 14, 1),
+				)
+				got, err := s.Update(test.id, test.update)
+				if got, want := err, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+				if diff := cmp.Diff(got, test.updated); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				// the person returned is a copy, changing it leaves the pool alone.
+				if got != nil {
+					got.NumberOfWantedDates = 99
+					if person, _ := s.Get(test.id); person.NumberOfWantedDates == 99 {
+						t.Errorf("%s got the person of the pool but want a copy", t.Name())
+					}
+				}
+				possible, _, err := s.PossibleMatches("1", nil, 5)
+				if err != nil && err != ErrNotFound {
+					t.Fatal(err)
+				}
+				var ids []string
+				for _, match := range possible {
+					ids = append(ids, match.ID)
+				}
+				if diff := cmp.Diff(ids, test.possible); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				counts := backend.count(t, s)
+				if got, want := counts[model.GenderMale]+counts[model.GenderFemale], 3; got != want {
+					t.Errorf("%s got %v people but want: %v", t.Name(), got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestUpdateWhileReading(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			t.Parallel()
+			s := setupTest(t, backend,
+				createPerson("f", model.GenderFemale, 10, 1),
+				createPerson("m", model.GenderMale, 12, 1),
 			)
-			defer teardown(t)
-			if got, want := Remove(test.id), test.expectedErr; got != want {
-				t.Errorf("%s got %v but want: %v", t.Name(), got, want)
-			}
-			if got, want := len(All), test.numPeople; got != want {
-				t.Errorf("%s got %v people but want: %v", t.Name(), got, want)
-			}
-			if got, want := len(peopleByGender[model.GenderMale]), test.numMales; got != want {
-				t.Errorf("%s got %v males but want: %v", t.Name(), got, want)
-			}
-			if got, want := len(peopleByGender[model.GenderFemale]), test.numFemales; got != want {
-				t.Errorf("%s got %v females but want: %v", t.Name(), got, want)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for i := range 100 {
+					name := strconv.Itoa(i)
+					if _, err := s.Update("m", &model.PersonUpdate{Name: &name}); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}()
+			// the people returned are read after the lock is released, as the handlers do.
+			for range 100 {
+				possible, _, err := s.PossibleMatches("f", nil, 5)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, candidate := range possible {
+					_ = candidate.Name
+				}
 			}
+			<-done
 		})
 	}
 }
@@ -160,18 +379,20 @@ func TestMatch(t *testing.T) {
 		},
 	}
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			teardown := setupTest(t, test.people...)
-			defer teardown(t)
-			got, gotErr := Match(test.id)
-			if diff := cmp.Diff(got, test.match); diff != "" {
-				t.Errorf("%s got want:\n%s", t.Name(), diff)
-			}
-			if got, want := gotErr, test.err; got != want {
-				t.Errorf("%s got %v but want: %v", t.Name(), got, want)
-			}
-		})
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend, test.people...)
+				got, gotErr := s.Match(test.id)
+				if diff := cmp.Diff(got, test.match); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if got, want := gotErr, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+			})
+		}
 	}
 }
 
@@ -242,9 +463,9 @@ func TestPossibleMatches(t *testing.T) {
 				createPerson("7", model.GenderFemale, 5, 1),
 			},
 			matches: People{
-				createPerson("3", model.GenderFemale, 1, 1),
-				createPerson("4", model.GenderFemale, 2, 1),
-				createPerson("5", model.GenderFemale, 3, 1),
+				createPerson("2", model.GenderFemale, 9, 1),
+				createPerson("7", model.GenderFemale, 5, 1),
+				createPerson("6", model.GenderFemale, 4, 1),
 			},
 		},
 		{
@@ -290,8 +511,8 @@ func TestPossibleMatches(t *testing.T) {
 				createPerson("3", model.GenderFemale, 2, 1),
 			},
 			matches: People{
-				createPerson("3", model.GenderFemale, 2, 1),
 				createPerson("2", model.GenderFemale, 9, 1),
+				createPerson("3", model.GenderFemale, 2, 1),
 			},
 		},
 		{
@@ -305,35 +526,178 @@ func TestPossibleMatches(t *testing.T) {
 		},
 	}
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			teardown := setupTest(t, test.people...)
-			defer teardown(t)
-			got, gotErr := PossibleMatches(test.id, test.n)
-			if diff := cmp.Diff(got, test.matches); diff != "" {
-				t.Errorf("%s got want:\n%s", t.Name(), diff)
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend, test.people...)
+				got, _, gotErr := s.PossibleMatches(test.id, nil, test.n)
+				if diff := cmp.Diff(got.People(), test.matches); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if got, want := gotErr, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestPreviousPartnersExcluded(t *testing.T) {
+	tests := []struct {
+		name    string
+		matches []string
+		id      string
+		n       int
+		want    People
+		err     error
+	}{
+		{
+			name:    "matched_partner_skipped",
+			matches: []string{"1"},
+			id:      "1",
+			n:       2,
+			want: People{
+				createPerson("3", model.GenderFemale, 8, 3),
+			},
+		},
+		{
+			name:    "excluded_both_ways",
+			matches: []string{"1"},
+			id:      "2",
+			n:       2,
+			want: People{
+				createPerson("4", model.GenderMale, 12, 3),
+			},
+		},
+		{
+			name:    "all_partners_matched",
+			matches: []string{"1", "1"},
+			id:      "1",
+			n:       2,
+			err:     ErrNotFound,
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend,
+					createPerson("1", model.GenderMale, 10, 3),
+					createPerson("2", model.GenderFemale, 9, 3),
+					createPerson("3", model.GenderFemale, 8, 3),
+					createPerson("4", model.GenderMale, 12, 3),
+				)
+				for _, id := range test.matches {
+					if _, err := s.Match(id); err != nil {
+						t.Fatal(err)
+					}
+				}
+				got, _, gotErr := s.PossibleMatches(test.id, nil, test.n)
+				if diff := cmp.Diff(got.People(), test.want); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if got, want := gotErr, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+				// Match shares the exclusion of PossibleMatches.
+				if test.err != nil {
+					if _, err := s.Match(test.id); err != ErrNotFound {
+						t.Errorf("%s got %v but want: %v", t.Name(), err, ErrNotFound)
+					}
+				}
+			})
+		}
+	}
+}
+
+// backend is a Store implementation the tests run against.
+type backend struct {
+	name string
+	new  func(tb testing.TB, opts ...Option) Store
+	// count returns the number of people in the pool by gender.
+	count func(tb testing.TB, s Store) map[model.Gender]int
+}
+
+var backends = []backend{
+	{
+		name: "memory",
+		new: func(tb testing.TB, opts ...Option) Store {
+			s := NewMemoryStore(opts...)
+			tb.Cleanup(func() { s.Close() })
+			return s
+		},
+		count: func(tb testing.TB, s Store) map[model.Gender]int { return countMemory(s.(*MemoryStore)) },
+	},
+	{
+		name: "durable",
+		new: func(tb testing.TB, opts ...Option) Store {
+			s, err := NewDurableStore(tb.TempDir(), DefaultSnapshotEvery, opts...)
+			if err != nil {
+				tb.Fatal(err)
 			}
-			if got, want := gotErr, test.err; got != want {
-				t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+			tb.Cleanup(func() { s.Close() })
+			return s
+		},
+		count: func(tb testing.TB, s Store) map[model.Gender]int { return countMemory(s.(*DurableStore).MemoryStore) },
+	},
+	{
+		name: "sqlite",
+		new: func(tb testing.TB, opts ...Option) Store {
+			s, err := NewSQLiteStore(filepath.Join(tb.TempDir(), "pool.db"), opts...)
+			if err != nil {
+				tb.Fatal(err)
 			}
-		})
-	}
+			tb.Cleanup(func() { s.Close() })
+			return s
+		},
+		count: func(tb testing.TB, s Store) map[model.Gender]int {
+			rows, err := s.(*SQLiteStore).db.Query(`SELECT gender, COUNT(*) FROM people GROUP BY gender`)
+			if err != nil {
+				tb.Fatal(err)
+			}
+			defer rows.Close()
+			counts := map[model.Gender]int{}
+			for rows.Next() {
+				var gender model.Gender
+				var n int
+				if err := rows.Scan(&gender, &n); err != nil {
+					tb.Fatal(err)
+				}
+				counts[gender] = n
+			}
+			return counts
+		},
+	},
 }
 
-func setupTest(tb testing.TB, people ...*Person) func(tb testing.TB) {
-	setupPeople(tb, people...)
-	return func(tb testing.TB) {
-		peopleByGender = map[model.Gender]People{}
-		peopleByGender[model.GenderFemale] = People{}
-		peopleByGender[model.GenderMale] = People{}
-		All = personById{}
+func countMemory(s *MemoryStore) map[model.Gender]int {
+	counts := map[model.Gender]int{}
+	for gender, index := range s.peopleByGender {
+		counts[gender] = index.size
 	}
+	return counts
+}
+
+func setupTest(tb testing.TB, backend backend, people ...*Person) Store {
+	tb.Helper()
+	return setupTestWithOptions(tb, backend, nil, people...)
 }
-func setupPeople(tb testing.TB, people ...*Person) {
+
+// setupTestWithOptions adds the people to a new store of the backend. The
+// clock of the store is stopped at testTime unless an option sets another
+// one, so that the people added by the test are equally active.
+func setupTestWithOptions(tb testing.TB, backend backend, opts []Option, people ...*Person) Store {
 	tb.Helper()
+	opts = append([]Option{WithClock(func() time.Time { return testTime })}, opts...)
+	s := backend.new(tb, opts...)
 	for _, person := range people {
-		Add(person.ID, &person.Person)
+		if _, err := s.Add(person.ID, &person.Person); err != nil {
+			tb.Fatal(err)
+		}
 	}
+	return s
 }
 
 func createPerson(id string, gender model.Gender, height int, numDates int) *Person {
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"errors"
+	"time"
+)
+
+// ReportReason is why a person is reported.
+type ReportReason string
+
+const (
+	ReasonSpam          ReportReason = "spam"
+	ReasonHarassment    ReportReason = "harassment"
+	ReasonFakeProfile   ReportReason = "fake_profile"
+	ReasonInappropriate ReportReason = "inappropriate"
+	ReasonUnderage      ReportReason = "underage"
+	ReasonOther         ReportReason = "other"
+)
+
+var ErrUnknownReason = errors.New("unknown report reason")
+
+// Valid reports whether the reason is one of the reason codes.
+func (r ReportReason) Valid() bool {
+	switch r {
+	case ReasonSpam, ReasonHarassment, ReasonFakeProfile, ReasonInappropriate, ReasonUnderage, ReasonOther:
+		return true
+	}
+	return false
+}
+
+// Report is a person reported by another one, kept after either of them is removed.
+type Report struct {
+	ID         string       `json:"id"`
+	Reporter   string       `json:"reporter"`
+	Target     string       `json:"target"`
+	Reason     ReportReason `json:"reason"`
+	Comment    string       `json:"comment,omitempty"`
+	ReportedAt time.Time    `json:"reported_at"`
+}
+
+// ReportFilter selects the reports of the given reason and target, an empty field selects any.
+type ReportFilter struct {
+	Reason ReportReason
+	Target string
+}
+
+func (f ReportFilter) matches(report *Report) bool {
+	return (f.Reason == "" || report.Reason == f.Reason) && (f.Target == "" || report.Target == f.Target)
+}
+
+// blocks keep the people blocked by every person, along with who blocked
+// every person so that a removed person is forgotten on both sides.
+type blocks struct {
+	blocked   map[string]map[string]struct{}
+	blockedBy map[string]map[string]struct{}
+}
+
+func newBlocks() *blocks {
+	return &blocks{blocked: map[string]map[string]struct{}{}, blockedBy: map[string]map[string]struct{}{}}
+}
+
+func (b *blocks) add(id string, target string) {
+	if b.blocked[id] == nil {
+		b.blocked[id] = map[string]struct{}{}
+	}
+	b.blocked[id][target] = struct{}{}
+	if b.blockedBy[target] == nil {
+		b.blockedBy[target] = map[string]struct{}{}
+	}
+	b.blockedBy[target][id] = struct{}{}
+}
+
+// between reports whether either person blocked the other one.
+func (b *blocks) between(id string, other string) bool {
+	_, blocked := b.blocked[id][other]
+	_, blockedBy := b.blockedBy[id][other]
+	return blocked || blockedBy
+}
+
+// forget forgets the blocks of the person and on the person.
+func (b *blocks) forget(id string) {
+	for target := range b.blocked[id] {
+		delete(b.blockedBy[target], id)
+		if len(b.blockedBy[target]) == 0 {
+			delete(b.blockedBy, target)
+		}
+	}
+	for blocker := range b.blockedBy[id] {
+		delete(b.blocked[blocker], id)
+		if len(b.blocked[blocker]) == 0 {
+			delete(b.blocked, blocker)
+		}
+	}
+	delete(b.blocked, id)
+	delete(b.blockedBy, id)
+}
+
+// blockTargets checks that the person and the target are two people in the pool.
+func (s *MemoryStore) blockTargets(id string, targetID string) error {
+	if id == targetID {
+		return ErrSamePerson
+	}
+	if _, err := s.all.getPerson(id); err != nil {
+		return err
+	}
+	_, err := s.all.getPerson(targetID)
+	return err
+}
+
+func (s *MemoryStore) Block(id string, targetID string) error {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	if err := s.blockTargets(id, targetID); err != nil {
+		return err
+	}
+	s.blocks.add(id, targetID)
+	return nil
+}
+
+// reportTargets checks that the person and the target are two people in the
+// pool, or two people matched to each other who may have been evicted since.
+func (s *MemoryStore) reportTargets(id string, targetID string) error {
+	if s.ledger.matched(id, targetID) {
+		return nil
+	}
+	return s.blockTargets(id, targetID)
+}
+
+// newReport returns the report of the target by the person.
+func (s *MemoryStore) newReport(id string, targetID string, reason ReportReason, comment string) (*Report, error) {
+	if !reason.Valid() {
+		return nil, ErrUnknownReason
+	}
+	if err := s.reportTargets(id, targetID); err != nil {
+		return nil, err
+	}
+	return &Report{
+		ID:         s.idGenerator.GenerateKey(),
+		Reporter:   id,
+		Target:     targetID,
+		Reason:     reason,
+		Comment:    comment,
+		ReportedAt: s.now().UTC(),
+	}, nil
+}
+
+func (s *MemoryStore) Report(id string, targetID string, reason ReportReason, comment string) (*Report, error) {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	report, err := s.newReport(id, targetID, reason, comment)
+	if err != nil {
+		return nil, err
+	}
+	s.reports = append(s.reports, report)
+	copied := *report
+	return &copied, nil
+}
+
+func (s *MemoryStore) Reports(filter ReportFilter) ([]Report, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	reports := []Report{}
+	for _, report := range s.reports {
+		if filter.matches(report) {
+			reports = append(reports, *report)
+		}
+	}
+	return reports, nil
+}
@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bito_interview/model"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		blocks   []swipe
+		likes    []swipe
+		possible []string
+		match    string
+		err      error
+	}{
+		{
+			name:     "no_blocks",
+			possible: []string{"2", "3"},
+			match:    "2",
+		},
+		{
+			name:     "block_target",
+			blocks:   []swipe{{id: "1", target: "2"}},
+			possible: []string{"3"},
+			match:    "3",
+		},
+		{
+			name:     "blocked_by_target",
+			blocks:   []swipe{{id: "2", target: "1"}},
+			possible: []string{"3"},
+			match:    "3",
+		},
+		{
+			name:     "block_twice",
+			blocks:   []swipe{{id: "1", target: "2"}, {id: "1", target: "2"}},
+			possible: []string{"3"},
+			match:    "3",
+		},
+		{
+			name:     "blocked_everyone",
+			blocks:   []swipe{{id: "1", target: "2"}, {id: "3", target: "1"}},
+			possible: nil,
+			err:      ErrNotFound,
+		},
+		{
+			name:     "blocked_mutual_like",
+			likes:    []swipe{{id: "2", target: "1"}},
+			blocks:   []swipe{{id: "2", target: "1"}},
+			possible: []string{"3"},
+			match:    "3",
+		},
+		{
+			name:     "same_person",
+			blocks:   []swipe{{id: "1", target: "1", err: ErrSamePerson}},
+			possible: []string{"2", "3"},
+			match:    "2",
+		},
+		{
+			name:     "unknown_target",
+			blocks:   []swipe{{id: "1", target: "5", err: ErrNotFound}, {id: "5", target: "1", err: ErrNotFound}},
+			possible: []string{"2", "3"},
+			match:    "2",
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend,
+					createPerson("1", model.GenderFemale, 10, 2),
+					createPerson("2", model.GenderMale, 12, 1),
+					createPerson("3", model.GenderMale, 14, 1),
+				)
+				for _, like := range test.likes {
+					if _, err := s.Like(like.id, like.target); err != nil {
+						t.Fatal(err)
+					}
+				}
+				for _, block := range test.blocks {
+					if got, want := s.Block(block.id, block.target), block.err; got != want {
+						t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+					}
+				}
+				if _, err := s.Like("1", "2"); err != nil {
+					t.Fatal(err)
+				}
+				got, _, err := s.PossibleMatches("1", nil, 5)
+				if got, want := err, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+				var ids []string
+				for _, match := range got {
+					ids = append(ids, match.ID)
+				}
+				if diff := cmp.Diff(ids, test.possible); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				match, err := s.Match("1")
+				if got, want := err, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+				if match != nil && match.ID != test.match {
+					t.Errorf("%s got %v but want: %v", t.Name(), match.ID, test.match)
+				}
+			})
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	type report struct {
+		id      string
+		target  string
+		reason  ReportReason
+		comment string
+		err     error
+	}
+	tests := []struct {
+		name    string
+		match   string
+		reports []report
+		filter  ReportFilter
+		want    []string
+	}{
+		{
+			name: "all",
+			reports: []report{
+				{id: "1", target: "2", reason: ReasonSpam, comment: "ads"},
+				{id: "3", target: "2", reason: ReasonFakeProfile},
+				{id: "2", target: "1", reason: ReasonSpam},
+			},
+			want: []string{"1>2:spam:ads", "3>2:fake_profile:", "2>1:spam:"},
+		},
+		{
+			name: "by_reason",
+			reports: []report{
+				{id: "1", target: "2", reason: ReasonSpam},
+				{id: "3", target: "2", reason: ReasonFakeProfile},
+				{id: "2", target: "1", reason: ReasonSpam},
+			},
+			filter: ReportFilter{Reason: ReasonSpam},
+			want:   []string{"1>2:spam:", "2>1:spam:"},
+		},
+		{
+			name: "by_target",
+			reports: []report{
+				{id: "1", target: "2", reason: ReasonSpam},
+				{id: "3", target: "2", reason: ReasonFakeProfile},
+				{id: "2", target: "1", reason: ReasonSpam},
+			},
+			filter: ReportFilter{Reason: ReasonSpam, Target: "2"},
+			want:   []string{"1>2:spam:"},
+		},
+		{
+			name: "invalid",
+			reports: []report{
+				{id: "1", target: "2", reason: "rude", err: ErrUnknownReason},
+				{id: "1", target: "1", reason: ReasonSpam, err: ErrSamePerson},
+				{id: "1", target: "5", reason: ReasonSpam, err: ErrNotFound},
+			},
+			want: []string{},
+		},
+		{
+			name:  "matched_and_evicted",
+			match: "1",
+			reports: []report{
+				{id: "1", target: "2", reason: ReasonHarassment},
+				{id: "2", target: "1", reason: ReasonSpam},
+				{id: "3", target: "2", reason: ReasonSpam, err: ErrNotFound},
+			},
+			want: []string{"1>2:harassment:", "2>1:spam:"},
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTestWithOptions(t, backend, []Option{WithClock(func() time.Time { return testTime })},
+					createPerson("1", model.GenderFemale, 10, 2),
+					createPerson("2", model.GenderMale, 12, 1),
+					createPerson("3", model.GenderFemale, 14, 1),
+				)
+				// 1 matches 2, who is evicted.
+				if test.match != "" {
+					if _, err := s.Match(test.match); err != nil {
+						t.Fatal(err)
+					}
+				}
+				for _, report := range test.reports {
+					got, err := s.Report(report.id, report.target, report.reason, report.comment)
+					if got, want := err, report.err; got != want {
+						t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+					}
+					if err == nil && (got.ID == "" || !got.ReportedAt.Equal(testTime)) {
+						t.Errorf("%s got report %+v", t.Name(), got)
+					}
+				}
+				reports, err := s.Reports(test.filter)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := []string{}
+				for _, report := range reports {
+					got = append(got, report.Reporter+">"+report.Target+":"+string(report.Reason)+":"+report.Comment)
+				}
+				if diff := cmp.Diff(got, test.want); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+			})
+		}
+	}
+}
+
+func TestDurableBlocksRecovery(t *testing.T) {
+	for _, snapshotEvery := range []int{2, 100} {
+		dir := t.TempDir()
+		s := openDurable(t, dir, snapshotEvery)
+		for _, person := range (People{
+			createPerson("1", model.GenderFemale, 10, 2),
+			createPerson("2", model.GenderMale, 12, 2),
+			createPerson("3", model.GenderMale, 14, 2),
+		}) {
+			if _, err := s.Add(person.ID, &person.Person); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.Block("2", "1"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Report("1", "2", ReasonHarassment, "rude"); err != nil {
+			t.Fatal(err)
+		}
+		s.Close()
+
+		s = openDurable(t, dir, snapshotEvery)
+		match, err := s.Match("1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := match.ID, "3"; got != want {
+			t.Errorf("%s got %v after recovery with snapshots every %v but want: %v", t.Name(), got, snapshotEvery, want)
+		}
+		reports, err := s.Reports(ReportFilter{Target: "2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(reports), 1; got != want {
+			t.Errorf("%s got %v reports after recovery with snapshots every %v but want: %v", t.Name(), got, snapshotEvery, want)
+		}
+		s.Close()
+	}
+}
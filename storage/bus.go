@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"sync"
+	"time"
+)
+
+// DomainEvent is a change of the pool published by the store: PersonAdded,
+// PersonRemoved, Matched or PersonEvicted.
+type DomainEvent interface {
+	domainEvent()
+}
+
+// PersonAdded is published when the person is added, one by one for the
+// people imported.
+type PersonAdded struct {
+	Person Person
+	At     time.Time
+}
+
+// PersonRemoved is published when the person is removed from the pool.
+type PersonRemoved struct {
+	PersonID string
+	At       time.Time
+}
+
+// Matched is published when two people are matched, before the eviction of
+// those who have no dates left.
+type Matched struct {
+	Match MatchRecord
+}
+
+// PersonEvicted is published when the match left the person no wanted dates
+// and the person was removed from the pool.
+type PersonEvicted struct {
+	PersonID string
+	Match    MatchRecord
+}
+
+func (PersonAdded) domainEvent()   {}
+func (PersonRemoved) domainEvent() {}
+func (Matched) domainEvent()       {}
+func (PersonEvicted) domainEvent() {}
+
+// matchedEvents returns the events of the match, the match followed by the
+// eviction of those who have no dates left.
+func matchedEvents(rec *MatchRecord) []DomainEvent {
+	events := []DomainEvent{Matched{Match: *rec}}
+	if rec.RemainingDatesA <= 0 {
+		events = append(events, PersonEvicted{PersonID: rec.PersonA, Match: *rec})
+	}
+	if rec.RemainingDatesB <= 0 {
+		events = append(events, PersonEvicted{PersonID: rec.PersonB, Match: *rec})
+	}
+	return events
+}
+
+// EventBus delivers the domain events published to every subscriber, in
+// the order they were published. Every subscriber handles the events from
+// its own goroutine and queues at most the size of its buffer, publishing
+// waits for a subscriber whose queue is full. The stores publish once their
+// write lock is released, so a slow handler only holds up the writer
+// publishing and a handler may read the store.
+type EventBus struct {
+	mutex       sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	events chan DomainEvent
+	done   chan struct{}
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: map[*subscriber]struct{}{}}
+}
+
+// Subscribe calls the handler with every event published afterwards and
+// returns the function unsubscribing the handler, which returns once the
+// events already queued are handled.
+func (b *EventBus) Subscribe(buffer int, handler func(event DomainEvent)) func() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	s := &subscriber{events: make(chan DomainEvent, buffer), done: make(chan struct{})}
+	b.subscribers[s] = struct{}{}
+	go func() {
+		defer close(s.done)
+		for event := range s.events {
+			handler(event)
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mutex.Lock()
+			delete(b.subscribers, s)
+			close(s.events)
+			b.mutex.Unlock()
+			<-s.done
+		})
+	}
+}
+
+// Publish queues the events for every subscriber, waiting for the
+// subscribers whose queue is full.
+func (b *EventBus) Publish(events ...DomainEvent) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, event := range events {
+		for s := range b.subscribers {
+			s.events <- event
+		}
+	}
+}
+
+// outbox keeps the events of the mutations of a store until they are
+// published. The store queues the events while holding its write lock, so
+// they are queued in the order of the mutations, and flushes the outbox once
+// the lock is released.
+type outbox struct {
+	mutex  sync.Mutex
+	events []DomainEvent
+	// publishing is held by the writer publishing the queued events, the
+	// other writers leave their events to it rather than waiting.
+	publishing sync.Mutex
+}
+
+func (o *outbox) queue(events ...DomainEvent) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.events = append(o.events, events...)
+}
+
+// take empties the outbox and returns the events it kept.
+func (o *outbox) take() []DomainEvent {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	events := o.events
+	o.events = nil
+	return events
+}
+
+func (o *outbox) empty() bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return len(o.events) == 0
+}
+
+// flush publishes the queued events to the bus unless another writer is
+// already publishing, who then publishes them too.
+func (o *outbox) flush(bus *EventBus) {
+	for o.publishing.TryLock() {
+		bus.Publish(o.take()...)
+		o.publishing.Unlock()
+		// the events queued while publishing were left to this writer.
+		if o.empty() {
+			return
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bito_interview/model"
+)
+
+const (
+	walFileName      = "wal.log"
+	snapshotFileName = "snapshot.json"
+
+	// DefaultSnapshotEvery is the number of logged mutations between two snapshots.
+	DefaultSnapshotEvery = 1000
+)
+
+// DurableStore is a MemoryStore whose mutations are recorded in a
+// write-ahead log and periodically compacted into a snapshot. Opening the
+// store on the same directory rebuilds the pool as it was before shutdown.
+type DurableStore struct {
+	*MemoryStore
+	dir           string
+	log           *wal
+	snapshotEvery int
+	pending       int
+}
+
+var _ Store = (*DurableStore)(nil)
+
+// snapshot is the pool and the match ledger as of the mutation with the given LSN.
+type snapshot struct {
+	LSN      uint64               `json:"lsn"`
+	People   People               `json:"people"`
+	ActiveAt map[string]time.Time `json:"active_at,omitempty"`
+	Matches  []*MatchRecord       `json:"matches"`
+	// Swipes are whether every person liked or passed on other people.
+	Swipes map[string]map[string]bool `json:"swipes,omitempty"`
+	// Blocks are the people blocked by every person.
+	Blocks  map[string]map[string]struct{} `json:"blocks,omitempty"`
+	Reports []*Report                      `json:"reports,omitempty"`
+}
+
+// NewDurableStore opens the store persisted in dir, creating dir when it
+// does not exist. A snapshot is taken after every snapshotEvery mutations,
+// or DefaultSnapshotEvery when it is not positive.
+func NewDurableStore(dir string, snapshotEvery int, opts ...Option) (*DurableStore, error) {
+	if snapshotEvery <= 0 {
+		snapshotEvery = DefaultSnapshotEvery
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	d := &DurableStore{MemoryStore: NewMemoryStore(opts...), dir: dir, snapshotEvery: snapshotEvery}
+	lsn, err := d.loadSnapshot()
+	if err != nil {
+		d.notifier.close()
+		return nil, err
+	}
+	d.log, err = openWAL(filepath.Join(dir, walFileName), lsn, d.apply)
+	if err != nil {
+		d.notifier.close()
+		return nil, err
+	}
+	// the events of the mutations replayed were published before the restart.
+	d.outbox.take()
+	d.pending = int(d.log.lsn - lsn)
+	return d, nil
+}
+
+func (d *DurableStore) loadSnapshot() (uint64, error) {
+	bytes, err := os.ReadFile(filepath.Join(d.dir, snapshotFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	snap := &snapshot{}
+	if err := json.Unmarshal(bytes, snap); err != nil {
+		return 0, fmt.Errorf("load snapshot: %w", err)
+	}
+	for _, person := range snap.People {
+		d.add(person.ID, &person.Person, snap.ActiveAt[person.ID])
+	}
+	for _, rec := range snap.Matches {
+		d.ledger.add(rec)
+	}
+	for id, targets := range snap.Swipes {
+		for target, liked := range targets {
+			d.swipes.set(id, target, liked)
+		}
+	}
+	for id, targets := range snap.Blocks {
+		for target := range targets {
+			d.blocks.add(id, target)
+		}
+	}
+	d.reports = snap.Reports
+	return snap.LSN, nil
+}
+
+// apply replays a logged mutation on the in-memory pool.
+func (d *DurableStore) apply(rec *walRecord) error {
+	switch rec.Op {
+	case walOpAdd:
+		if rec.Person == nil {
+			return errors.New("add without person")
+		}
+		d.add(rec.ID, rec.Person, rec.At)
+	case walOpImport:
+		d.importPeople(rec.People, rec.At)
+	case walOpRemove:
+		person, err := d.all.getPerson(rec.ID)
+		if err != nil {
+			return err
+		}
+		d.remove(person)
+	case walOpUpdate:
+		if rec.Person == nil {
+			return errors.New("update without person")
+		}
+		person, err := d.all.getPerson(rec.ID)
+		if err != nil {
+			return err
+		}
+		d.update(person, rec.Person)
+	case walOpMatch:
+		if rec.Match == nil {
+			return errors.New("match without record")
+		}
+		person, err := d.all.getPerson(rec.Match.PersonA)
+		if err != nil {
+			return err
+		}
+		match, err := d.all.getPerson(rec.Match.PersonB)
+		if err != nil {
+			return err
+		}
+		d.match(person, match, rec.Match)
+	case walOpLike, walOpPass:
+		person, err := d.all.getPerson(rec.ID)
+		if err != nil {
+			return err
+		}
+		target, err := d.all.getPerson(rec.Target)
+		if err != nil {
+			return err
+		}
+		if rec.Op == walOpPass {
+			d.swipes.set(rec.ID, rec.Target, false)
+		} else {
+			d.like(person, target, rec.Match)
+		}
+	case walOpBlock:
+		d.blocks.add(rec.ID, rec.Target)
+	case walOpReport:
+		if rec.Report == nil {
+			return errors.New("report without report")
+		}
+		d.reports = append(d.reports, rec.Report)
+	default:
+		return fmt.Errorf("unknown op %q", rec.Op)
+	}
+	return nil
+}
+
+// record appends the mutation to the log. The caller must hold the write
+// lock and apply the mutation on the pool afterwards.
+func (d *DurableStore) record(rec walRecord) error {
+	if err := d.log.append(rec); err != nil {
+		return err
+	}
+	d.pending++
+	return nil
+}
+
+// compact snapshots the pool once enough mutations have been logged. The
+// mutation is already durable in the log so a failure is only reported and
+// the snapshot is retried after the next mutation.
+func (d *DurableStore) compact() {
+	if d.pending < d.snapshotEvery {
+		return
+	}
+	if err := d.snapshot(); err != nil {
+		log.Printf("snapshot %s: %v", d.dir, err)
+	}
+}
+
+func (d *DurableStore) Add(id string, person *model.Person) (*Person, error) {
+	defer d.flush()
+	d.rwMutex.Lock()
+	defer d.rwMutex.Unlock()
+	at := d.now()
+	if err := d.record(walRecord{Op: walOpAdd, ID: id, Person: person, At: at}); err != nil {
+		return nil, err
+	}
+	newPerson := d.add(id, person, at)
+	d.compact()
+	return newPerson, nil
+}
+
+func (d *DurableStore) Import(people People) error {
+	defer d.flush()
+	d.rwMutex.Lock()
+	defer d.rwMutex.Unlock()
+	at := d.now()
+	if err := d.record(walRecord{Op: walOpImport, People: people, At: at}); err != nil {
+		return err
+	}
+	d.importPeople(people, at)
+	d.compact()
+	return nil
+}
+
+func (d *DurableStore) Remove(id string) error {
+	defer d.flush()
+	d.rwMutex.Lock()
+	defer d.rwMutex.Unlock()
+	person, err := d.all.getPerson(id)
+	if err != nil {
+		return err
+	}
+	if err := d.record(walRecord{Op: walOpRemove, ID: id}); err != nil {
+		return err
+	}
+	d.remove(person)
+	d.publish(PersonRemoved{PersonID: id, At: d.now().UTC()})
+	d.compact()
+	return nil
+}
+
+func (d *DurableStore) Update(id string, update *model.PersonUpdate) (*Person, error) {
+	d.rwMutex.Lock()
+	defer d.rwMutex.Unlock()
+	person, err := d.all.getPerson(id)
+	if err != nil {
+		return nil, err
+	}
+	updated := person.Person
+	update.Apply(&updated)
+	if err := d.record(walRecord{Op: walOpUpdate, ID: id, Person: &updated}); err != nil {
+		return nil, err
+	}
+	copied := *d.update(person, &updated)
+	d.compact()
+	return &copied, nil
+}
+
+func (d *DurableStore) Match(id string) (*Person, error) {
+	defer d.flush()
+	d.rwMutex.Lock()
+	defer d.rwMutex.Unlock()
+	person, match, err := d.findMatch(id)
+	if err != nil {
+		return nil, err
+	}
+	matchRecord := d.newMatchRecord(person, match)
+	if err := d.record(walRecord{Op: walOpMatch, Match: matchRecord}); err != nil {
+		return nil, err
+	}
+	d.match(person, match, matchRecord)
+	d.compact()
+	return match, nil
+}
+
+func (d *DurableStore) Like(id string, targetID string) (*MatchRecord, error) {
+	defer d.flush()
+	d.rwMutex.Lock()
+	defer d.rwMutex.Unlock()
+	person, target, err := d.swipeTargets(id, targetID)
+	if err != nil {
+		return nil, err
+	}
+	rec := walRecord{Op: walOpLike, ID: id, Target: targetID}
+	if d.likedBack(id, targetID) {
+		rec.Match = d.newMatchRecord(person, target)
+	}
+	if err := d.record(rec); err != nil {
+		return nil, err
+	}
+	match := d.like(person, target, rec.Match)
+	d.compact()
+	return match, nil
+}
+
+func (d *DurableStore) Pass(id string, targetID string) error {
+	d.rwMutex.Lock()
+	defer d.rwMutex.Unlock()
+	if _, _, err := d.swipeTargets(id, targetID); err != nil {
+		return err
+	}
+	if err := d.record(walRecord{Op: walOpPass, ID: id, Target: targetID}); err != nil {
+		return err
+	}
+	d.swipes.set(id, targetID, false)
+	d.compact()
+	return nil
+}
+
+func (d *DurableStore) Block(id string, targetID string) error {
+	d.rwMutex.Lock()
+	defer d.rwMutex.Unlock()
+	if err := d.blockTargets(id, targetID); err != nil {
+		return err
+	}
+	if err := d.record(walRecord{Op: walOpBlock, ID: id, Target: targetID}); err != nil {
+		return err
+	}
+	d.blocks.add(id, targetID)
+	d.compact()
+	return nil
+}
+
+func (d *DurableStore) Report(id string, targetID string, reason ReportReason, comment string) (*Report, error) {
+	d.rwMutex.Lock()
+	defer d.rwMutex.Unlock()
+	report, err := d.newReport(id, targetID, reason, comment)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.record(walRecord{Op: walOpReport, Report: report}); err != nil {
+		return nil, err
+	}
+	d.reports = append(d.reports, report)
+	d.compact()
+	copied := *report
+	return &copied, nil
+}
+
+// Snapshot writes the whole pool to the snapshot file and empties the log.
+func (d *DurableStore) Snapshot() error {
+	d.rwMutex.Lock()
+	defer d.rwMutex.Unlock()
+	return d.snapshot()
+}
+
+func (d *DurableStore) snapshot() error {
+	snap := &snapshot{
+		LSN:      d.log.lsn,
+		People:   make(People, 0, len(d.all)),
+		ActiveAt: d.activeAt,
+		Matches:  d.ledger.records,
+		Swipes:   d.swipes.liked,
+		Blocks:   d.blocks.blocked,
+		Reports:  d.reports,
+	}
+	for _, person := range d.all {
+		snap.People = append(snap.People, person)
+	}
+	bytes, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	// the log is only emptied once the snapshot is in place, records already
+	// covered by the snapshot are skipped by their LSN when replaying.
+	tmp := filepath.Join(d.dir, snapshotFileName+".tmp")
+	if err := writeFileSync(tmp, bytes); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(d.dir, snapshotFileName)); err != nil {
+		return err
+	}
+	// the rename must be durable before the log is emptied, or a crash may
+	// leave the previous snapshot along with an empty log.
+	if err := syncDir(d.dir); err != nil {
+		return err
+	}
+	if err := d.log.reset(); err != nil {
+		return err
+	}
+	d.pending = 0
+	return nil
+}
+
+// Close closes the log and the channels of the subscribers. The store must
+// not be used afterwards.
+func (d *DurableStore) Close() error {
+	d.MemoryStore.Close()
+	return d.log.close()
+}
+
+// syncDir flushes the entries of the directory, such as a file renamed in it.
+func syncDir(name string) error {
+	dir, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	if err := dir.Sync(); err != nil {
+		dir.Close()
+		return err
+	}
+	return dir.Close()
+}
+
+func writeFileSync(name string, data []byte) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bito_interview/model"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDurableStoreRecovery(t *testing.T) {
+	tests := []struct {
+		name          string
+		snapshotEvery int
+		corrupt       func(t *testing.T, dir string)
+		people        People
+	}{
+		{
+			name:          "replay_wal",
+			snapshotEvery: 100,
+			people: People{
+				createPerson("3", model.GenderFemale, 7, 2),
+			},
+		},
+		{
+			name:          "snapshot_and_wal",
+			snapshotEvery: 3,
+			people: People{
+				createPerson("3", model.GenderFemale, 7, 2),
+			},
+		},
+		{
+			name:          "torn_final_record",
+			snapshotEvery: 100,
+			corrupt: func(t *testing.T, dir string) {
+				file, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_APPEND|os.O_WRONLY, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer file.Close()
+				if _, err := file.Write([]byte{0, 0, 0, 42, 1, 2}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			people: People{
+				createPerson("3", model.GenderFemale, 7, 2),
+			},
+		},
+		{
+			name:          "corrupt_record_length",
+			snapshotEvery: 100,
+			corrupt: func(t *testing.T, dir string) {
+				file, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_APPEND|os.O_WRONLY, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer file.Close()
+				if _, err := file.Write([]byte{0xff, 0xff, 0xff, 0xff, 1, 2, 3, 4}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			people: People{
+				createPerson("3", model.GenderFemale, 7, 2),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			s := openDurable(t, dir, test.snapshotEvery)
+			people := People{
+				createPerson("1", model.GenderMale, 10, 2),
+				createPerson("2", model.GenderFemale, 9, 1),
+				createPerson("3", model.GenderFemale, 8, 3),
+				createPerson("4", model.GenderMale, 12, 1),
+			}
+			for _, person := range people {
+				if _, err := s.Add(person.ID, &person.Person); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// 1 matches 3 then 2, both 1 and 2 are evicted, 4 is removed and 3 shrinks.
+			for range 2 {
+				if _, err := s.Match("1"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := s.Remove("4"); err != nil {
+				t.Fatal(err)
+			}
+			height := 7
+			if _, err := s.Update("3", &model.PersonUpdate{Height: &height}); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if test.corrupt != nil {
+				test.corrupt(t, dir)
+			}
+
+			s = openDurable(t, dir, test.snapshotEvery)
+			defer s.Close()
+			if diff := cmp.Diff(peopleOf(s.MemoryStore), test.people); diff != "" {
+				t.Errorf("%s got want:\n%s", t.Name(), diff)
+			}
+			history, err := s.History("1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(history), 2; got != want {
+				t.Errorf("%s got %v matches but want: %v", t.Name(), got, want)
+			}
+			// the recovered log keeps accepting mutations.
+			if _, err := s.Add("5", &createPerson("5", model.GenderMale, 1, 1).Person); err != nil {
+				t.Fatal(err)
+			}
+			s.Close()
+			s = openDurable(t, dir, test.snapshotEvery)
+			defer s.Close()
+			if _, err := s.all.getPerson("5"); err != nil {
+				t.Errorf("%s got %v but want person 5", t.Name(), err)
+			}
+		})
+	}
+}
+
+func TestDurableImportRecovery(t *testing.T) {
+	for _, snapshotEvery := range []int{1, 100} {
+		dir := t.TempDir()
+		s := openDurable(t, dir, snapshotEvery)
+		imported := People{
+			createPerson("1", model.GenderFemale, 10, 2),
+			createPerson("2", model.GenderMale, 12, 1),
+		}
+		if err := s.Import(imported); err != nil {
+			t.Fatal(err)
+		}
+		s.Close()
+
+		s = openDurable(t, dir, snapshotEvery)
+		if diff := cmp.Diff(peopleOf(s.MemoryStore), People{imported[1], imported[0]}); diff != "" {
+			t.Errorf("%s got want:\n%s", t.Name(), diff)
+		}
+		s.Close()
+	}
+}
+
+func openDurable(t *testing.T, dir string, snapshotEvery int) *DurableStore {
+	t.Helper()
+	s, err := NewDurableStore(dir, snapshotEvery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// peopleOf returns everyone in the pool ordered by gender then height.
+func peopleOf(s *MemoryStore) People {
+	var people People
+	for _, gender := range []model.Gender{model.GenderMale, model.GenderFemale} {
+		ranges := s.peopleByGender[gender].ranges(math.MinInt, math.MaxInt, math.MinInt, math.MaxInt, true)
+		walk(ranges, OrderAscending, func(person *Person) bool {
+			people = append(people, person)
+			return true
+		})
+	}
+	return people
+}
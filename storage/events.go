@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType is the kind of change of the pool affecting a person.
+type EventType string
+
+const (
+	// EventMatched is published to both people of a match.
+	EventMatched EventType = "match"
+	// EventRemoved is published to the person removed from the pool.
+	EventRemoved EventType = "removal"
+	// EventEvicted is published to the person evicted from the pool after a
+	// match left him/her no wanted dates.
+	EventEvicted EventType = "eviction"
+)
+
+// Event is a change of the pool affecting the person.
+type Event struct {
+	Type     EventType `json:"type"`
+	PersonID string    `json:"person_id"`
+	// Match is the match of the person, set on match and eviction events.
+	Match *MatchRecord `json:"match,omitempty"`
+	At    time.Time    `json:"at"`
+}
+
+// NotificationsOf returns the events of the people affected by the domain
+// event: both people of a match, the person removed and the person evicted.
+// Nobody is notified of the people added.
+func NotificationsOf(event DomainEvent) []Event {
+	switch e := event.(type) {
+	case PersonRemoved:
+		return []Event{{Type: EventRemoved, PersonID: e.PersonID, At: e.At}}
+	case Matched:
+		a, b := e.Match, e.Match
+		return []Event{
+			{Type: EventMatched, PersonID: e.Match.PersonA, Match: &a, At: e.Match.MatchedAt},
+			{Type: EventMatched, PersonID: e.Match.PersonB, Match: &b, At: e.Match.MatchedAt},
+		}
+	case PersonEvicted:
+		match := e.Match
+		return []Event{{Type: EventEvicted, PersonID: e.PersonID, Match: &match, At: e.Match.MatchedAt}}
+	}
+	return nil
+}
+
+// subscriberBuffer is the number of events a subscriber may fall behind
+// before it is unsubscribed.
+const subscriberBuffer = 16
+
+// notifier delivers the events of a person to the subscribers of the
+// person, in the order the domain events were published to the bus.
+// Notifying never waits for a subscriber, a subscriber whose buffer is full
+// is unsubscribed and its channel closed instead.
+type notifier struct {
+	mutex       sync.Mutex
+	subscribers map[string]map[chan Event]struct{}
+	// stop unsubscribes the notifier from the bus.
+	stop func()
+}
+
+// newNotifier returns the notifier of the domain events published to the bus.
+func newNotifier(bus *EventBus) *notifier {
+	n := &notifier{subscribers: map[string]map[chan Event]struct{}{}}
+	n.stop = bus.Subscribe(subscriberBuffer, func(event DomainEvent) {
+		n.notify(NotificationsOf(event)...)
+	})
+	return n
+}
+
+// close unsubscribes the notifier from the bus and closes the channels of
+// every subscriber.
+func (n *notifier) close() {
+	n.stop()
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for personID, subscribers := range n.subscribers {
+		for events := range subscribers {
+			n.unsubscribe(personID, events)
+		}
+	}
+}
+
+// Subscribe returns the channel of the events of the person and the function
+// unsubscribing from them, which closes the channel.
+func (n *notifier) Subscribe(personID string) (<-chan Event, func()) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	events := make(chan Event, subscriberBuffer)
+	if n.subscribers[personID] == nil {
+		n.subscribers[personID] = map[chan Event]struct{}{}
+	}
+	n.subscribers[personID][events] = struct{}{}
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			n.mutex.Lock()
+			defer n.mutex.Unlock()
+			n.unsubscribe(personID, events)
+		})
+	}
+}
+
+// unsubscribe closes the channel unless it was already unsubscribed.
+func (n *notifier) unsubscribe(personID string, events chan Event) {
+	if _, ok := n.subscribers[personID][events]; !ok {
+		return
+	}
+	delete(n.subscribers[personID], events)
+	if len(n.subscribers[personID]) == 0 {
+		delete(n.subscribers, personID)
+	}
+	close(events)
+}
+
+// notify delivers every event to the subscribers of its person.
+func (n *notifier) notify(events ...Event) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for _, event := range events {
+		for subscriber := range n.subscribers[event.PersonID] {
+			select {
+			case subscriber <- event:
+			default:
+				n.unsubscribe(event.PersonID, subscriber)
+			}
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bito_interview/model"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDomainEvents(t *testing.T) {
+	match := func(remainingA int, remainingB int) MatchRecord {
+		return MatchRecord{ID: "match-1", PersonA: "1", PersonB: "2", MatchedAt: testTime, RemainingDatesA: remainingA, RemainingDatesB: remainingB}
+	}
+	tests := []struct {
+		name   string
+		dates  int
+		mutate func(s Store) error
+		err    error
+		want   []DomainEvent
+	}{
+		{
+			name:  "match",
+			dates: 2,
+			mutate: func(s Store) error {
+				_, err := s.Match("1")
+				return err
+			},
+			want: []DomainEvent{
+				Matched{Match: match(1, 0)},
+				PersonEvicted{PersonID: "2", Match: match(1, 0)},
+			},
+		},
+		{
+			name:  "mutual_like",
+			dates: 1,
+			mutate: func(s Store) error {
+				if _, err := s.Like("2", "1"); err != nil {
+					return err
+				}
+				_, err := s.Like("1", "2")
+				return err
+			},
+			want: []DomainEvent{
+				Matched{Match: match(0, 0)},
+				PersonEvicted{PersonID: "1", Match: match(0, 0)},
+				PersonEvicted{PersonID: "2", Match: match(0, 0)},
+			},
+		},
+		{
+			name:  "remove",
+			dates: 1,
+			mutate: func(s Store) error {
+				return s.Remove("1")
+			},
+			want: []DomainEvent{PersonRemoved{PersonID: "1", At: testTime}},
+		},
+		{
+			name:  "remove_unknown",
+			dates: 1,
+			mutate: func(s Store) error {
+				return s.Remove("3")
+			},
+			err: ErrNotFound,
+		},
+		{
+			name:  "import",
+			dates: 1,
+			mutate: func(s Store) error {
+				return s.Import(People{createPerson("3", model.GenderMale, 14, 1), createPerson("4", model.GenderMale, 16, 1)})
+			},
+			want: []DomainEvent{
+				PersonAdded{Person: *createPerson("3", model.GenderMale, 14, 1), At: testTime},
+				PersonAdded{Person: *createPerson("4", model.GenderMale, 16, 1), At: testTime},
+			},
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				bus := NewEventBus()
+				var got []DomainEvent
+				unsubscribe := bus.Subscribe(0, func(event DomainEvent) {
+					got = append(got, event)
+				})
+				s := setupTestWithOptions(t, backend, []Option{
+					WithIDGenerator(FakeIDGenerator{FakeID: "match-1"}),
+					WithClock(func() time.Time { return testTime }),
+					WithEventBus(bus),
+				},
+					createPerson("1", model.GenderFemale, 10, test.dates),
+					createPerson("2", model.GenderMale, 12, 1),
+				)
+				if err := test.mutate(s); err != test.err {
+					t.Fatalf("%s got %v but want: %v", t.Name(), err, test.err)
+				}
+				unsubscribe()
+				want := append([]DomainEvent{
+					PersonAdded{Person: *createPerson("1", model.GenderFemale, 10, test.dates), At: testTime},
+					PersonAdded{Person: *createPerson("2", model.GenderMale, 12, 1), At: testTime},
+				}, test.want...)
+				if diff := cmp.Diff(got, want); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+			})
+		}
+	}
+}
+
+func TestDurableReplayPublishesNothing(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewDurableStore(dir, DefaultSnapshotEvery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, person := range (People{createPerson("1", model.GenderFemale, 10, 1), createPerson("2", model.GenderMale, 12, 1)}) {
+		if _, err := s.Add(person.ID, &person.Person); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Match("1"); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	bus := NewEventBus()
+	var got []DomainEvent
+	unsubscribe := bus.Subscribe(0, func(event DomainEvent) {
+		got = append(got, event)
+	})
+	s, err = NewDurableStore(dir, DefaultSnapshotEvery, WithEventBus(bus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	unsubscribe()
+	if len(got) != 0 {
+		t.Errorf("%s got %v but want none", t.Name(), got)
+	}
+}
+
+func TestEventBus(t *testing.T) {
+	bus := NewEventBus()
+	release := make(chan struct{})
+	var slow, fast []DomainEvent
+	unsubscribeSlow := bus.Subscribe(1, func(event DomainEvent) {
+		<-release
+		slow = append(slow, event)
+	})
+	unsubscribeFast := bus.Subscribe(10, func(event DomainEvent) {
+		fast = append(fast, event)
+	})
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for i := 0; i < 3; i++ {
+			bus.Publish(PersonRemoved{PersonID: string(rune('a' + i))})
+		}
+	}()
+	// the slow subscriber holds the third event, one being handled and one queued.
+	select {
+	case <-published:
+		t.Fatalf("%s got events published past a full queue", t.Name())
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-published
+	unsubscribeSlow()
+	unsubscribeFast()
+	// events published after unsubscribing are not handled.
+	bus.Publish(PersonRemoved{PersonID: "d"})
+	want := []DomainEvent{PersonRemoved{PersonID: "a"}, PersonRemoved{PersonID: "b"}, PersonRemoved{PersonID: "c"}}
+	if diff := cmp.Diff(slow, want); diff != "" {
+		t.Errorf("%s got want:\n%s", t.Name(), diff)
+	}
+	if diff := cmp.Diff(fast, want); diff != "" {
+		t.Errorf("%s got want:\n%s", t.Name(), diff)
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	bus := NewEventBus()
+	n := newNotifier(bus)
+	events, cancel := n.Subscribe("1")
+	other, cancelOther := n.Subscribe("1")
+	defer cancelOther()
+	rec := MatchRecord{ID: "match-1", PersonA: "1", PersonB: "2", MatchedAt: testTime, RemainingDatesA: 0, RemainingDatesB: 1}
+	bus.Publish(PersonAdded{Person: *createPerson("1", model.GenderFemale, 10, 1)}, PersonRemoved{PersonID: "2"},
+		Matched{Match: rec}, PersonEvicted{PersonID: "1", Match: rec})
+	want := []Event{
+		{Type: EventMatched, PersonID: "1", Match: &rec, At: testTime},
+		{Type: EventEvicted, PersonID: "1", Match: &rec, At: testTime},
+	}
+	for _, event := range want {
+		got := receive(t, other)
+		if diff := cmp.Diff(got, event); diff != "" {
+			t.Errorf("%s got want:\n%s", t.Name(), diff)
+		}
+	}
+	// a subscriber falling behind is unsubscribed without holding up the others.
+	for i := 0; i < subscriberBuffer-1; i++ {
+		bus.Publish(PersonRemoved{PersonID: "1"})
+		receive(t, other)
+	}
+	received := 0
+	for range events {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("%s got %v events but want: %v", t.Name(), received, subscriberBuffer)
+	}
+	// unsubscribing twice or after falling behind does nothing.
+	cancel()
+	cancel()
+	// closing the notifier closes the channels left.
+	n.close()
+	if _, ok := <-other; ok {
+		t.Errorf("%s got channel open after close", t.Name())
+	}
+}
+
+func TestSlowSubscriberHoldsUpNobody(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			t.Parallel()
+			bus := NewEventBus()
+			release := make(chan struct{})
+			var s Store
+			var got []string
+			unsubscribe := bus.Subscribe(0, func(event DomainEvent) {
+				<-release
+				// the handler reads the store while writers wait for it.
+				person, err := s.Get(event.(PersonAdded).Person.ID)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				got = append(got, person.ID)
+			})
+			s = backend.new(t, WithEventBus(bus))
+			// the first event is handled and the second one waits for the handler.
+			added := make(chan struct{})
+			go func() {
+				defer close(added)
+				for _, id := range []string{"1", "2"} {
+					if _, err := s.Add(id, &createPerson(id, model.GenderFemale, 10, 1).Person); err != nil {
+						t.Error(err)
+					}
+				}
+			}()
+			time.Sleep(10 * time.Millisecond)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				if _, err := s.Add("3", &createPerson("3", model.GenderMale, 12, 1).Person); err != nil {
+					t.Error(err)
+				}
+				if _, err := s.Get("3"); err != nil {
+					t.Error(err)
+				}
+			}()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatalf("%s got the store held up by a slow subscriber", t.Name())
+			}
+			close(release)
+			<-added
+			unsubscribe()
+			if diff := cmp.Diff(got, []string{"1", "2", "3"}); diff != "" {
+				t.Errorf("%s got want:\n%s", t.Name(), diff)
+			}
+		})
+	}
+}
+
+func receive(tb testing.TB, events <-chan Event) Event {
+	tb.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		tb.Fatal("no event received")
+	}
+	return Event{}
+}
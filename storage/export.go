@@ -0,0 +1,54 @@
+package storage
+
+import "math"
+
+// exportChunkSize is the number of people copied under the read lock at a
+// time when exporting.
+const exportChunkSize = 100
+
+// exported is a person copied for the export along with his/her history.
+type exported struct {
+	person  Person
+	history []MatchRecord
+}
+
+// Export visits everyone in the pool in the order of height then id, along
+// with his/her match history oldest first. The people are copied under the
+// read lock a chunk at a time and visited once the lock is released, so
+// that neither the whole pool is held in memory nor a slow visit holds up
+// the writers. Every chunk resumes after the last person visited, the
+// people added, changed or removed meanwhile after that person may be part
+// of the export.
+func (s *MemoryStore) Export(visit func(person *Person, history []MatchRecord) error) error {
+	var after *Cursor
+	for {
+		chunk := s.exportChunk(after)
+		for i := range chunk {
+			if err := visit(&chunk[i].person, chunk[i].history); err != nil {
+				return err
+			}
+		}
+		if len(chunk) < exportChunkSize {
+			return nil
+		}
+		after = cursorOf(&chunk[len(chunk)-1].person)
+	}
+}
+
+// exportChunk copies the people after the cursor, at most exportChunkSize of them.
+func (s *MemoryStore) exportChunk(after *Cursor) []exported {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	var ranges []People
+	for _, index := range s.peopleByGender {
+		for _, people := range index.ranges(math.MinInt, math.MaxInt, math.MinInt, math.MaxInt, true) {
+			ranges = append(ranges, after.trim(people, OrderAscending))
+		}
+	}
+	chunk := make([]exported, 0, exportChunkSize)
+	walk(ranges, OrderAscending, func(person *Person) bool {
+		chunk = append(chunk, exported{person: *person, history: s.ledger.history(person.ID)})
+		return len(chunk) < exportChunkSize
+	})
+	return chunk
+}
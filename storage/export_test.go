@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/bito_interview/model"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExport(t *testing.T) {
+	errStop := errors.New("stop")
+	tests := []struct {
+		name      string
+		stopAfter int
+		err       error
+		people    People
+		histories []int
+	}{
+		{
+			name:      "everyone",
+			people:    People{createPerson("4", model.GenderFemale, 8, 1), createPerson("1", model.GenderFemale, 10, 1), createPerson("3", model.GenderMale, 14, 2)},
+			histories: []int{0, 1, 0},
+		},
+		{
+			name:      "stopped",
+			stopAfter: 2,
+			err:       errStop,
+			people:    People{createPerson("4", model.GenderFemale, 8, 1), createPerson("1", model.GenderFemale, 10, 1)},
+			histories: []int{0, 1},
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend,
+					createPerson("1", model.GenderFemale, 10, 2),
+					createPerson("2", model.GenderMale, 12, 1),
+					createPerson("3", model.GenderMale, 14, 2),
+					createPerson("4", model.GenderFemale, 8, 1),
+				)
+				// 1 matches 2, who is evicted.
+				if _, err := s.Match("1"); err != nil {
+					t.Fatal(err)
+				}
+				var people People
+				var histories []int
+				err := s.Export(func(person *Person, history []MatchRecord) error {
+					people = append(people, person)
+					histories = append(histories, len(history))
+					if len(people) == test.stopAfter {
+						return errStop
+					}
+					return nil
+				})
+				if got, want := err, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+				if diff := cmp.Diff(people, test.people); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if diff := cmp.Diff(histories, test.histories); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+			})
+		}
+	}
+}
+
+func TestExportHoldsUpNobody(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			t.Parallel()
+			s := setupTest(t, backend,
+				createPerson("1", model.GenderFemale, 10, 1),
+				createPerson("2", model.GenderMale, 12, 1),
+			)
+			var ids []string
+			err := s.Export(func(person *Person, history []MatchRecord) error {
+				ids = append(ids, person.ID)
+				if len(ids) > 1 {
+					return nil
+				}
+				// the store is read and changed while the export is visiting.
+				if _, err := s.Get("2"); err != nil {
+					return err
+				}
+				_, err := s.Add("3", &createPerson("3", model.GenderMale, 14, 1).Person)
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the export is the pool before the person was added.
+			if diff := cmp.Diff(ids, []string{"1", "2"}); diff != "" {
+				t.Errorf("%s got want:\n%s", t.Name(), diff)
+			}
+		})
+	}
+}
+
+func TestExportInChunks(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			t.Parallel()
+			var people People
+			for i := range 2*exportChunkSize + 10 {
+				people = append(people, createPerson(strconv.Itoa(i), model.GenderFemale, 1+i%7, 1))
+			}
+			s := setupTest(t, backend, people...)
+			var got People
+			err := s.Export(func(person *Person, history []MatchRecord) error {
+				got = append(got, person)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			slices.SortFunc(people, heightCmp)
+			if diff := cmp.Diff(got, people); diff != "" {
+				t.Errorf("%s got want:\n%s", t.Name(), diff)
+			}
+		})
+	}
+}
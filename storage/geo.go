@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"math"
+
+	"github.com/bito_interview/model"
+)
+
+const (
+	// gridCellDegrees is the side of the cells of the location grid, about 55km at the equator.
+	gridCellDegrees = 0.5
+	// kmPerDegree is the length of a degree of latitude.
+	kmPerDegree = model.EarthRadiusKm * math.Pi / 180
+	// gridColumns is the number of cells around a parallel.
+	gridColumns = int(360 / gridCellDegrees)
+)
+
+// maxDistance returns the location and the maximum distance of the partners
+// of the person, false when the person does not bound the distance.
+func maxDistance(person *Person) (*model.Location, float64, bool) {
+	if person.Location == nil || person.Preferences == nil || person.Preferences.MaxDistanceKm == nil {
+		return nil, 0, false
+	}
+	return person.Location, *person.Preferences.MaxDistanceKm, true
+}
+
+// boundingBox is the range of latitudes and longitudes around a location,
+// minLng is greater than maxLng when the box crosses the antimeridian.
+type boundingBox struct {
+	minLat, maxLat float64
+	minLng, maxLng float64
+}
+
+// boundingBoxOf returns the box containing every location within radiusKm of the center.
+func boundingBoxOf(center *model.Location, radiusKm float64) boundingBox {
+	dLat := radiusKm / kmPerDegree
+	box := boundingBox{
+		minLat: max(center.Latitude-dLat, -90),
+		maxLat: min(center.Latitude+dLat, 90),
+		minLng: -180,
+		maxLng: 180,
+	}
+	if box.minLat == -90 || box.maxLat == 90 {
+		return box
+	}
+	// the longitudes span the most at the latitude of the box closest to a pole.
+	cos := math.Cos(max(math.Abs(box.minLat), math.Abs(box.maxLat)) * math.Pi / 180)
+	dLng := dLat / cos
+	if dLng >= 180 {
+		return box
+	}
+	box.minLng, box.maxLng = center.Longitude-dLng, center.Longitude+dLng
+	if box.minLng < -180 {
+		box.minLng += 360
+	}
+	if box.maxLng > 180 {
+		box.maxLng -= 360
+	}
+	return box
+}
+
+type gridCell struct {
+	row, column int
+}
+
+func cellOf(latitude float64, longitude float64) gridCell {
+	return gridCell{
+		row:    int(math.Floor(latitude / gridCellDegrees)),
+		column: (int(math.Floor(longitude/gridCellDegrees)) + gridColumns/2) % gridColumns,
+	}
+}
+
+// locationGrid indexes the people whose location is known by the cell of
+// the grid containing it, so that looking up the people around a location
+// only visits the cells overlapping its bounding box.
+type locationGrid map[gridCell]personById
+
+func (g locationGrid) insert(person *Person) {
+	if person.Location == nil {
+		return
+	}
+	cell := cellOf(person.Location.Latitude, person.Location.Longitude)
+	if _, ok := g[cell]; !ok {
+		g[cell] = personById{}
+	}
+	g[cell][person.ID] = person
+}
+
+func (g locationGrid) remove(person *Person) {
+	if person.Location == nil {
+		return
+	}
+	cell := cellOf(person.Location.Latitude, person.Location.Longitude)
+	delete(g[cell], person.ID)
+	if len(g[cell]) == 0 {
+		delete(g, cell)
+	}
+}
+
+// near returns the people in the cells overlapping the bounding box of the
+// circle, which includes everyone within radiusKm of the center and some
+// people farther away.
+func (g locationGrid) near(center *model.Location, radiusKm float64) People {
+	box := boundingBoxOf(center, radiusKm)
+	low, high := cellOf(box.minLat, box.minLng), cellOf(box.maxLat, box.maxLng)
+	if box.minLng == -180 && box.maxLng == 180 {
+		low.column, high.column = 0, gridColumns-1
+	}
+	columns := (high.column-low.column+gridColumns)%gridColumns + 1
+	people := People{}
+	visit := func(cell personById) {
+		for _, person := range cell {
+			people = append(people, person)
+		}
+	}
+	// a wide box has more cells than the grid has occupied ones.
+	if (high.row-low.row+1)*columns > len(g) {
+		for cell, inCell := range g {
+			if cell.row >= low.row && cell.row <= high.row &&
+				(cell.column-low.column+gridColumns)%gridColumns < columns {
+				visit(inCell)
+			}
+		}
+		return people
+	}
+	for row := low.row; row <= high.row; row++ {
+		for i := range columns {
+			visit(g[gridCell{row: row, column: (low.column + i) % gridColumns}])
+		}
+	}
+	return people
+}
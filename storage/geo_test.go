@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/bito_interview/model"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	taipei    = &model.Location{Latitude: 25.033, Longitude: 121.565}
+	taoyuan   = &model.Location{Latitude: 24.993, Longitude: 121.301}
+	taichung  = &model.Location{Latitude: 24.148, Longitude: 120.674}
+	suva      = &model.Location{Latitude: -18.141, Longitude: 178.442}
+	apia      = &model.Location{Latitude: -13.833, Longitude: -171.767}
+	northPole = &model.Location{Latitude: 89.9, Longitude: 0}
+)
+
+func TestPossibleMatchesWithDistance(t *testing.T) {
+	tests := []struct {
+		name      string
+		people    People
+		matches   []string
+		distances []*float64
+		err       error
+	}{
+		{
+			name: "no_max_distance",
+			people: People{
+				withLocation(createPerson("1", model.GenderFemale, 10, 1), taipei),
+				withLocation(createPerson("2", model.GenderMale, 12, 1), taoyuan),
+				withLocation(createPerson("3", model.GenderMale, 14, 1), taichung),
+				createPerson("4", model.GenderMale, 16, 1),
+			},
+			matches:   []string{"2", "3", "4"},
+			distances: []*float64{distance(27), distance(133), nil},
+		},
+		{
+			name: "max_distance",
+			people: People{
+				withPreferences(withLocation(createPerson("1", model.GenderFemale, 10, 1), taipei), maxDistancePreferences(100)),
+				withLocation(createPerson("2", model.GenderMale, 12, 1), taoyuan),
+				withLocation(createPerson("3", model.GenderMale, 14, 1), taichung),
+				createPerson("4", model.GenderMale, 16, 1),
+				withLocation(createPerson("5", model.GenderFemale, 18, 1), taipei),
+			},
+			matches:   []string{"2"},
+			distances: []*float64{distance(27)},
+		},
+		{
+			name: "candidate_max_distance",
+			people: People{
+				withLocation(createPerson("1", model.GenderFemale, 10, 1), taipei),
+				withPreferences(withLocation(createPerson("2", model.GenderMale, 12, 1), taoyuan), maxDistancePreferences(10)),
+				withLocation(createPerson("3", model.GenderMale, 14, 1), taichung),
+				createPerson("4", model.GenderMale, 16, 1),
+			},
+			matches:   []string{"3", "4"},
+			distances: []*float64{distance(133), nil},
+		},
+		{
+			name: "unknown_location",
+			people: People{
+				withPreferences(createPerson("1", model.GenderFemale, 10, 1), maxDistancePreferences(100)),
+				withLocation(createPerson("2", model.GenderMale, 12, 1), taoyuan),
+				createPerson("3", model.GenderMale, 14, 1),
+			},
+			err: ErrNotFound,
+		},
+		{
+			name: "across_antimeridian",
+			people: People{
+				withPreferences(withLocation(createPerson("1", model.GenderFemale, 10, 1), suva), maxDistancePreferences(1500)),
+				withLocation(createPerson("2", model.GenderMale, 12, 1), apia),
+				withLocation(createPerson("3", model.GenderMale, 14, 1), taipei),
+			},
+			matches:   []string{"2"},
+			distances: []*float64{distance(1151)},
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend, test.people...)
+				got, _, gotErr := s.PossibleMatches("1", nil, 5)
+				var ids []string
+				var distances []*float64
+				for _, match := range got {
+					ids = append(ids, match.ID)
+					if match.DistanceKm != nil {
+						distances = append(distances, distance(math.Round(*match.DistanceKm)))
+					} else {
+						distances = append(distances, nil)
+					}
+				}
+				if diff := cmp.Diff(ids, test.matches); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if diff := cmp.Diff(distances, test.distances); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if got, want := gotErr, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestLocationGridNear(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	grid := locationGrid{}
+	var people People
+	for i := range 2000 {
+		location := &model.Location{Latitude: random.Float64()*180 - 90, Longitude: random.Float64()*360 - 180}
+		person := withLocation(createPerson(strconv.Itoa(i), model.GenderMale, 10, 1), location)
+		grid.insert(person)
+		people = append(people, person)
+	}
+	centers := []*model.Location{taipei, suva, apia, northPole}
+	for _, center := range centers {
+		for _, radiusKm := range []float64{50, 500, 3000, 20000} {
+			near := personById{}
+			for _, person := range grid.near(center, radiusKm) {
+				near[person.ID] = person
+			}
+			for _, person := range people {
+				if _, ok := near[person.ID]; !ok && model.DistanceKm(center, person.Location) <= radiusKm {
+					t.Errorf("%v within %vkm got no %v at %v", center, radiusKm, person.ID, person.Location)
+				}
+			}
+		}
+	}
+}
+
+func withLocation(person *Person, location *model.Location) *Person {
+	person.Location = location
+	return person
+}
+
+func maxDistancePreferences(km float64) *model.Preferences {
+	return &model.Preferences{MaxDistanceKm: &km}
+}
+
+func distance(km float64) *float64 {
+	return &km
+}
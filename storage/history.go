@@ -0,0 +1,70 @@
+package storage
+
+import "time"
+
+// MatchRecord is the ledger entry of a match between two people.
+type MatchRecord struct {
+	ID string `json:"id"`
+	// PersonA asked for the match and PersonB was matched to PersonA.
+	PersonA   string    `json:"person_a"`
+	PersonB   string    `json:"person_b"`
+	MatchedAt time.Time `json:"matched_at"`
+	// RemainingDatesA and RemainingDatesB are the wanted dates left after the match.
+	RemainingDatesA int `json:"remaining_dates_a"`
+	RemainingDatesB int `json:"remaining_dates_b"`
+}
+
+// matchLedger keeps every match in order and by its id and the people
+// involved, along with the partners each person was already matched to.
+type matchLedger struct {
+	records  []*MatchRecord
+	byID     map[string]*MatchRecord
+	byPerson map[string][]*MatchRecord
+	partners map[string]map[string]struct{}
+}
+
+func newMatchLedger() *matchLedger {
+	return &matchLedger{
+		byID:     map[string]*MatchRecord{},
+		byPerson: map[string][]*MatchRecord{},
+		partners: map[string]map[string]struct{}{},
+	}
+}
+
+func (l *matchLedger) add(rec *MatchRecord) {
+	l.records = append(l.records, rec)
+	l.byID[rec.ID] = rec
+	l.byPerson[rec.PersonA] = append(l.byPerson[rec.PersonA], rec)
+	l.byPerson[rec.PersonB] = append(l.byPerson[rec.PersonB], rec)
+	l.addPartner(rec.PersonA, rec.PersonB)
+	l.addPartner(rec.PersonB, rec.PersonA)
+}
+
+func (l *matchLedger) addPartner(id string, partnerID string) {
+	if l.partners[id] == nil {
+		l.partners[id] = map[string]struct{}{}
+	}
+	l.partners[id][partnerID] = struct{}{}
+}
+
+// matched reports whether the two people were already matched to each other.
+func (l *matchLedger) matched(id string, partnerID string) bool {
+	_, ok := l.partners[id][partnerID]
+	return ok
+}
+
+func (l *matchLedger) get(id string) (*MatchRecord, error) {
+	if rec, ok := l.byID[id]; ok {
+		copied := *rec
+		return &copied, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (l *matchLedger) history(personID string) []MatchRecord {
+	history := make([]MatchRecord, 0, len(l.byPerson[personID]))
+	for _, rec := range l.byPerson[personID] {
+		history = append(history, *rec)
+	}
+	return history
+}
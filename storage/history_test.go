@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bito_interview/model"
+	"github.com/google/go-cmp/cmp"
+)
+
+var testTime = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+func TestHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		people  People
+		matches []string
+		id      string
+		history []MatchRecord
+		err     error
+	}{
+		{
+			name: "person_not_found",
+			id:   "not_found",
+			err:  ErrNotFound,
+		},
+		{
+			name: "no_match",
+			people: People{
+				createPerson("1", model.GenderMale, 10, 1),
+			},
+			id:      "1",
+			history: []MatchRecord{},
+		},
+		{
+			name: "evicted_person",
+			people: People{
+				createPerson("1", model.GenderMale, 10, 1),
+				createPerson("2", model.GenderFemale, 9, 2),
+				createPerson("3", model.GenderMale, 12, 1),
+			},
+			matches: []string{"1", "3"},
+			id:      "2",
+			history: []MatchRecord{
+				{ID: "match-1", PersonA: "1", PersonB: "2", MatchedAt: testTime, RemainingDatesA: 0, RemainingDatesB: 1},
+				{ID: "match-2", PersonA: "3", PersonB: "2", MatchedAt: testTime, RemainingDatesA: 0, RemainingDatesB: 0},
+			},
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTestWithOptions(t, backend, historyOptions(), test.people...)
+				for _, id := range test.matches {
+					if _, err := s.Match(id); err != nil {
+						t.Fatal(err)
+					}
+				}
+				got, gotErr := s.History(test.id)
+				if diff := cmp.Diff(got, test.history); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if got, want := gotErr, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestGetMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		matchID string
+		match   *MatchRecord
+		err     error
+	}{
+		{
+			name:    "not_found",
+			matchID: "not_found",
+			err:     ErrNotFound,
+		},
+		{
+			name:    "success",
+			matchID: "match-1",
+			match:   &MatchRecord{ID: "match-1", PersonA: "1", PersonB: "2", MatchedAt: testTime, RemainingDatesA: 0, RemainingDatesB: 2},
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTestWithOptions(t, backend, historyOptions(),
+					createPerson("1", model.GenderMale, 10, 1),
+					createPerson("2", model.GenderFemale, 9, 3),
+				)
+				if _, err := s.Match("1"); err != nil {
+					t.Fatal(err)
+				}
+				got, gotErr := s.GetMatch(test.matchID)
+				if diff := cmp.Diff(got, test.match); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if got, want := gotErr, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+			})
+		}
+	}
+}
+
+func historyOptions() []Option {
+	return []Option{
+		WithIDGenerator(&sequenceIDGenerator{prefix: "match-"}),
+		WithClock(func() time.Time { return testTime }),
+	}
+}
+
+// sequenceIDGenerator generates the ids prefix1, prefix2 and so on.
+type sequenceIDGenerator struct {
+	prefix string
+	next   atomic.Int64
+}
+
+func (g *sequenceIDGenerator) GenerateKey() string {
+	return g.prefix + strconv.FormatInt(g.next.Add(1), 10)
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"math"
+	"slices"
+
+	"github.com/bito_interview/model"
+)
+
+// unknownBirthYear is the bucket of the people whose birth date is unknown.
+const unknownBirthYear = 0
+
+// genderIndex is the secondary index of the people of a gender. People are
+// bucketed by birth year and every bucket is sorted by height, so looking up
+// a height and age range only searches the buckets of the birth years in
+// the age range.
+type genderIndex struct {
+	byYear map[int]People
+	// years are the sorted birth years having people.
+	years []int
+	size  int
+}
+
+func newGenderIndex() *genderIndex {
+	return &genderIndex{byYear: map[int]People{}}
+}
+
+func birthYear(person *Person) int {
+	if birth, ok := person.Birth(); ok {
+		return birth.Year()
+	}
+	return unknownBirthYear
+}
+
+func (g *genderIndex) insert(person *Person) {
+	year := birthYear(person)
+	if _, ok := g.byYear[year]; !ok {
+		index, _ := slices.BinarySearch(g.years, year)
+		g.years = slices.Insert(g.years, index, year)
+	}
+	g.byYear[year] = insert(g.byYear[year], person)
+	g.size++
+}
+
+func (g *genderIndex) remove(person *Person) {
+	year := birthYear(person)
+	people, ok := g.byYear[year]
+	if !ok {
+		return
+	}
+	if people = remove(people, person); len(people) == len(g.byYear[year]) {
+		return
+	}
+	g.size--
+	if len(people) > 0 {
+		g.byYear[year] = people
+		return
+	}
+	delete(g.byYear, year)
+	index, _ := slices.BinarySearch(g.years, year)
+	g.years = slices.Delete(g.years, index, index+1)
+}
+
+// ranges returns the people within the inclusive height range in every
+// bucket of the birth years between fromYear and toYear, and in the bucket
+// of unknown birth dates when withUnknown is set.
+func (g *genderIndex) ranges(low int, high int, fromYear int, toYear int, withUnknown bool) []People {
+	if g == nil {
+		return nil
+	}
+	var ranges []People
+	if withUnknown {
+		if people := heightRange(g.byYear[unknownBirthYear], low, high); len(people) > 0 {
+			ranges = append(ranges, people)
+		}
+	}
+	start, _ := slices.BinarySearch(g.years, max(fromYear, unknownBirthYear+1))
+	for _, year := range g.years[start:] {
+		if year > toYear {
+			break
+		}
+		if people := heightRange(g.byYear[year], low, high); len(people) > 0 {
+			ranges = append(ranges, people)
+		}
+	}
+	return ranges
+}
+
+// heightRange returns the part of the height sorted people within the inclusive height range.
+func heightRange(people People, low int, high int) People {
+	if low > high {
+		return nil
+	}
+	start, _ := slices.BinarySearchFunc(people, heightKey(low), heightCmp)
+	end := len(people)
+	if high < math.MaxInt {
+		end, _ = slices.BinarySearchFunc(people, heightKey(high+1), heightCmp)
+	}
+	if start >= end {
+		return nil
+	}
+	return people[start:end]
+}
+
+// heightKey is the search key of the first person of the given height.
+func heightKey(height int) *Person {
+	return &Person{Person: model.Person{PersonAttributes: model.PersonAttributes{Height: height}}}
+}
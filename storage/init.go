@@ -2,14 +2,23 @@ package storage
 
 import (
 	"sync"
+	"time"
 
 	"github.com/bito_interview/model"
 )
 
-func init() {
-	peopleByGender = map[model.Gender]People{}
-	peopleByGender[model.GenderFemale] = People{}
-	peopleByGender[model.GenderMale] = People{}
-	All = personById{}
-	rwMutex = &sync.RWMutex{}
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore(opts ...Option) *MemoryStore {
+	s := &MemoryStore{
+		peopleByGender: map[model.Gender]*genderIndex{},
+		locations:      locationGrid{},
+		all:            personById{},
+		activeAt:       map[string]time.Time{},
+		ledger:         newMatchLedger(),
+		swipes:         newSwipes(),
+		blocks:         newBlocks(),
+		rwMutex:        &sync.RWMutex{},
+		options:        newOptions(opts...),
+	}
+	return s
 }
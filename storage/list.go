@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/bito_interview/model"
+)
+
+// PeopleFilter selects the people listed, every field left zero selects anyone.
+type PeopleFilter struct {
+	Gender    model.Gender
+	MinHeight int
+	MaxHeight int
+	// MinDates and MaxDates bound the number of dates the people still want.
+	MinDates   int
+	MaxDates   int
+	NamePrefix string
+}
+
+// heights returns the inclusive height range of the filter.
+func (f PeopleFilter) heights() (int, int) {
+	low, high := math.MinInt, math.MaxInt
+	if f.MinHeight != 0 {
+		low = f.MinHeight
+	}
+	if f.MaxHeight != 0 {
+		high = f.MaxHeight
+	}
+	return low, high
+}
+
+func (f PeopleFilter) matches(person *Person) bool {
+	low, high := f.heights()
+	return (f.Gender == "" || person.Gender == f.Gender) &&
+		person.Height >= low && person.Height <= high &&
+		(f.MinDates == 0 || person.NumberOfWantedDates >= f.MinDates) &&
+		(f.MaxDates == 0 || person.NumberOfWantedDates <= f.MaxDates) &&
+		strings.HasPrefix(person.Name, f.NamePrefix)
+}
+
+// Cursor is the position of a person in the order of height then id, the
+// people after a cursor are not shifted by people added or removed before it.
+// The cursor of possible matches ranked by score also holds the score of the
+// person, ties being broken by height then id.
+type Cursor struct {
+	Height int      `json:"height"`
+	ID     string   `json:"id"`
+	Score  *float64 `json:"score,omitempty"`
+}
+
+func cursorOf(person *Person) *Cursor {
+	return &Cursor{Height: person.Height, ID: person.ID}
+}
+
+// ErrCursorOrder is returned when paging possible matches with the cursor
+// of another order, such as a cursor of height order on matches ranked by score.
+var ErrCursorOrder = errors.New("cursor of possible matches in another order")
+
+func (c *Cursor) key() *Person {
+	key := heightKey(c.Height)
+	key.ID = c.ID
+	return key
+}
+
+// candidate returns the candidate at the position of the cursor in score order.
+func (c *Cursor) candidate() Candidate {
+	return Candidate{Person: c.key(), Score: *c.Score}
+}
+
+// checkOrder checks that the cursor of possible matches is the cursor of the order.
+func (c *Cursor) checkOrder(order Order) error {
+	if c != nil && (c.Score != nil) != (order == OrderScore) {
+		return ErrCursorOrder
+	}
+	return nil
+}
+
+// before reports whether the person comes after the cursor.
+func (c *Cursor) before(person *Person) bool {
+	return heightCmp(c.key(), person) < 0
+}
+
+// trim returns the part of the height sorted people coming after the cursor
+// in the given order, all of them when the cursor is nil.
+func (c *Cursor) trim(people People, order Order) People {
+	if c == nil {
+		return people
+	}
+	index, found := slices.BinarySearchFunc(people, c.key(), heightCmp)
+	if order == OrderDescending {
+		return people[:index]
+	}
+	if found {
+		index++
+	}
+	return people[index:]
+}
+
+// PeoplePage is a page of the people selected by a filter in the order of
+// height then id.
+type PeoplePage struct {
+	People People
+	// Next is the cursor of the next page, nil on the last page.
+	Next *Cursor
+	// Total is the number of people selected by the filter in every page.
+	Total int
+}
+
+// List walks the gender indexes in the order of height then id, counting
+// everyone selected by the filter and keeping at most limit people after
+// the cursor.
+func (s *MemoryStore) List(filter PeopleFilter, after *Cursor, limit int) (*PeoplePage, error) {
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+	low, high := filter.heights()
+	var ranges []People
+	for gender, index := range s.peopleByGender {
+		if filter.Gender == "" || gender == filter.Gender {
+			ranges = append(ranges, index.ranges(low, high, math.MinInt, math.MaxInt, true)...)
+		}
+	}
+	page := &PeoplePage{People: People{}}
+	walk(ranges, OrderAscending, func(person *Person) bool {
+		if !filter.matches(person) {
+			return true
+		}
+		page.Total++
+		if after != nil && !after.before(person) {
+			return true
+		}
+		if len(page.People) < limit {
+			copied := *person
+			page.People = append(page.People, &copied)
+		} else if page.Next == nil && len(page.People) > 0 {
+			page.Next = cursorOf(page.People[len(page.People)-1])
+		}
+		return true
+	})
+	return page, nil
+}
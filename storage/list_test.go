@@ -0,0 +1,264 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/bito_interview/model"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter PeopleFilter
+		after  *Cursor
+		limit  int
+		want   []string
+		next   *Cursor
+		total  int
+	}{
+		{
+			name:  "everyone",
+			limit: 10,
+			want:  []string{"4", "1", "2", "3", "5"},
+			total: 5,
+		},
+		{
+			name:  "first_page",
+			limit: 2,
+			want:  []string{"4", "1"},
+			next:  &Cursor{Height: 10, ID: "1"},
+			total: 5,
+		},
+		{
+			name:  "middle_page",
+			after: &Cursor{Height: 10, ID: "1"},
+			limit: 2,
+			want:  []string{"2", "3"},
+			next:  &Cursor{Height: 12, ID: "3"},
+			total: 5,
+		},
+		{
+			name:  "same_height_page",
+			after: &Cursor{Height: 12, ID: "2"},
+			limit: 2,
+			want:  []string{"3", "5"},
+			total: 5,
+		},
+		{
+			name:  "last_page",
+			after: &Cursor{Height: 12, ID: "3"},
+			limit: 2,
+			want:  []string{"5"},
+			total: 5,
+		},
+		{
+			name:  "after_everyone",
+			after: &Cursor{Height: 20, ID: ""},
+			limit: 2,
+			want:  []string{},
+			total: 5,
+		},
+		{
+			name:   "gender",
+			filter: PeopleFilter{Gender: model.GenderMale},
+			limit:  10,
+			want:   []string{"2", "3"},
+			total:  2,
+		},
+		{
+			name:   "height_range",
+			filter: PeopleFilter{MinHeight: 10, MaxHeight: 12},
+			limit:  10,
+			want:   []string{"1", "2", "3"},
+			total:  3,
+		},
+		{
+			name:   "dates",
+			filter: PeopleFilter{MinDates: 2, MaxDates: 2},
+			limit:  10,
+			want:   []string{"1", "5"},
+			total:  2,
+		},
+		{
+			name:   "name_prefix",
+			filter: PeopleFilter{NamePrefix: "A"},
+			limit:  1,
+			want:   []string{"4"},
+			next:   &Cursor{Height: 8, ID: "4"},
+			total:  2,
+		},
+		{
+			name:   "nobody",
+			filter: PeopleFilter{Gender: model.GenderFemale, NamePrefix: "B"},
+			limit:  10,
+			want:   []string{},
+			total:  0,
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend,
+					withName(createPerson("1", model.GenderFemale, 10, 2), "Amy"),
+					withName(createPerson("2", model.GenderMale, 12, 1), "Bob"),
+					withName(createPerson("3", model.GenderMale, 12, 3), "Ben"),
+					withName(createPerson("4", model.GenderFemale, 8, 1), "Ann"),
+					withName(createPerson("5", model.GenderNonBinary, 14, 2), "Cat"),
+				)
+				page, err := s.List(test.filter, test.after, test.limit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids := []string{}
+				for _, person := range page.People {
+					ids = append(ids, person.ID)
+				}
+				if diff := cmp.Diff(ids, test.want); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if diff := cmp.Diff(page.Next, test.next); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if got, want := page.Total, test.total; got != want {
+					t.Errorf("%s got %v people in total but want: %v", t.Name(), got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestListStableAcrossInserts(t *testing.T) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			t.Parallel()
+			s := setupTest(t, backend,
+				createPerson("1", model.GenderFemale, 10, 1),
+				createPerson("2", model.GenderMale, 12, 1),
+				createPerson("3", model.GenderMale, 14, 1),
+				createPerson("4", model.GenderFemale, 16, 1),
+			)
+			var ids []string
+			var after *Cursor
+			for added := 0; ; added++ {
+				page, err := s.List(PeopleFilter{}, after, 1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, person := range page.People {
+					ids = append(ids, person.ID)
+				}
+				if page.Next == nil {
+					break
+				}
+				after = page.Next
+				// people added before the cursor do not shift the pages.
+				id := string(rune('a' + added))
+				if _, err := s.Add(id, &createPerson(id, model.GenderMale, 1, 1).Person); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if diff := cmp.Diff(ids, []string{"1", "2", "3", "4"}); diff != "" {
+				t.Errorf("%s got want:\n%s", t.Name(), diff)
+			}
+		})
+	}
+}
+
+func withName(person *Person, name string) *Person {
+	person.Name = name
+	return person
+}
+
+func TestPossibleMatchesPages(t *testing.T) {
+	tests := []struct {
+		name  string
+		order Order
+		want  []string
+	}{
+		{
+			name:  "ascending",
+			order: OrderAscending,
+			want:  []string{"a", "b", "c", "d", "e", "g"},
+		},
+		{
+			name:  "descending",
+			order: OrderDescending,
+			want:  []string{"e", "d", "c", "b", "z"},
+		},
+		{
+			// the closer the height the higher the score, z scores above the cursor.
+			name:  "score",
+			order: OrderScore,
+			want:  []string{"a", "b", "c", "d", "e", "g"},
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				rules := DefaultRules()
+				rules.Order = test.order
+				rules.Weights = &ScoreWeights{Height: 1}
+				s := setupTestWithOptions(t, backend, []Option{WithRules(rules)},
+					createPerson("1", model.GenderFemale, 10, 5),
+					createPerson("a", model.GenderMale, 11, 1),
+					createPerson("b", model.GenderMale, 12, 1),
+					createPerson("c", model.GenderMale, 13, 1),
+					createPerson("d", model.GenderMale, 14, 1),
+					createPerson("e", model.GenderMale, 15, 1),
+				)
+				var ids []string
+				page, next, err := s.PossibleMatches("1", nil, 2)
+				for pages := 1; err == nil; pages++ {
+					for _, match := range page {
+						ids = append(ids, match.ID)
+					}
+					if next == nil {
+						break
+					}
+					if pages == 1 {
+						// the pages after the cursor follow the people added and removed.
+						if err := s.Remove("a"); err != nil {
+							t.Fatal(err)
+						}
+						for _, person := range (People{
+							createPerson("z", model.GenderMale, 11, 1),
+							createPerson("g", model.GenderMale, 16, 1),
+						}) {
+							if _, err := s.Add(person.ID, &person.Person); err != nil {
+								t.Fatal(err)
+							}
+						}
+					}
+					page, next, err = s.PossibleMatches("1", next, 2)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(ids, test.want); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+			})
+		}
+	}
+	for _, backend := range backends {
+		t.Run(backend.name+"/cursor_order", func(t *testing.T) {
+			t.Parallel()
+			rules := DefaultRules()
+			rules.Order = OrderScore
+			s := setupTestWithOptions(t, backend, []Option{WithRules(rules)},
+				createPerson("1", model.GenderFemale, 10, 5),
+				createPerson("a", model.GenderMale, 11, 1),
+				createPerson("b", model.GenderMale, 12, 1),
+			)
+			// a cursor of height order does not page the matches ranked by score.
+			if _, _, err := s.PossibleMatches("1", &Cursor{Height: 11, ID: "a"}, 1); err != ErrCursorOrder {
+				t.Errorf("%s got %v but want: %v", t.Name(), err, ErrCursorOrder)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package storage
+
+import "time"
+
+type options struct {
+	idGenerator IDGenerator
+	now         func() time.Time
+	rules       Rules
+	strategy    MatchStrategy
+	bus         *EventBus
+	outbox      *outbox
+	notifier    *notifier
+}
+
+// Option configures a Store.
+type Option func(*options)
+
+// WithIDGenerator sets the generator of match ids, UUIDGenerator by default.
+func WithIDGenerator(idGenerator IDGenerator) Option {
+	return func(o *options) {
+		o.idGenerator = idGenerator
+	}
+}
+
+// WithClock sets the clock timestamping matches, time.Now by default.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithRules sets the matching rules, DefaultRules by default.
+func WithRules(rules Rules) Option {
+	return func(o *options) {
+		o.rules = rules
+	}
+}
+
+// WithMatchStrategy sets the strategy picking the partner of Match, FirstMatch by default.
+func WithMatchStrategy(strategy MatchStrategy) Option {
+	return func(o *options) {
+		o.strategy = strategy
+	}
+}
+
+// WithEventBus sets the bus the domain events are published to, a new one by default.
+func WithEventBus(bus *EventBus) Option {
+	return func(o *options) {
+		o.bus = bus
+	}
+}
+
+func newOptions(opts ...Option) options {
+	o := options{
+		idGenerator: UUIDGenerator{},
+		now:         time.Now,
+		rules:       DefaultRules(),
+		strategy:    FirstMatch{},
+		bus:         NewEventBus(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.outbox = &outbox{}
+	o.notifier = newNotifier(o.bus)
+	return o
+}
+
+// publish queues the events of a mutation, the caller must hold the write
+// lock of the store.
+func (o *options) publish(events ...DomainEvent) {
+	o.outbox.queue(events...)
+}
+
+// flush publishes the events queued, the caller must not hold the write
+// lock of the store.
+func (o *options) flush() {
+	o.outbox.flush(o.bus)
+}
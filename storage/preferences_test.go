@@ -0,0 +1,271 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bito_interview/model"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPossibleMatchesWithPreferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		people  People
+		matches People
+		match   *Person
+		err     error
+	}{
+		{
+			name: "no_preferences",
+			people: People{
+				createPerson("1", model.GenderFemale, 10, 1),
+				createPerson("2", model.GenderMale, 12, 1),
+				createPerson("3", model.GenderMale, 14, 1),
+			},
+			matches: People{
+				createPerson("2", model.GenderMale, 12, 1),
+				createPerson("3", model.GenderMale, 14, 1),
+			},
+			match: createPerson("2", model.GenderMale, 12, 0),
+		},
+		{
+			name: "preferred_height",
+			people: People{
+				withPreferences(createPerson("1", model.GenderFemale, 10, 1), &model.Preferences{Height: &model.HeightRange{Min: 13, Max: 20}}),
+				createPerson("2", model.GenderMale, 12, 1),
+				createPerson("3", model.GenderMale, 14, 1),
+			},
+			matches: People{
+				createPerson("3", model.GenderMale, 14, 1),
+			},
+			match: createPerson("3", model.GenderMale, 14, 0),
+		},
+		{
+			name: "candidate_preferred_height",
+			people: People{
+				createPerson("1", model.GenderFemale, 10, 1),
+				withPreferences(createPerson("2", model.GenderMale, 12, 1), &model.Preferences{Height: &model.HeightRange{Min: 5, Max: 9}}),
+				createPerson("3", model.GenderMale, 14, 1),
+			},
+			matches: People{
+				createPerson("3", model.GenderMale, 14, 1),
+			},
+			match: createPerson("3", model.GenderMale, 14, 0),
+		},
+		{
+			name: "not_seeking_candidate_gender",
+			people: People{
+				withPreferences(createPerson("1", model.GenderFemale, 10, 1), &model.Preferences{Seeking: []model.Gender{model.GenderFemale}}),
+				createPerson("2", model.GenderMale, 12, 1),
+			},
+			err: ErrNotFound,
+		},
+		{
+			name: "candidate_not_seeking_gender",
+			people: People{
+				createPerson("1", model.GenderFemale, 10, 1),
+				withPreferences(createPerson("2", model.GenderMale, 12, 1), &model.Preferences{Seeking: []model.Gender{model.GenderMale}}),
+				withPreferences(createPerson("3", model.GenderMale, 14, 1), &model.Preferences{Seeking: []model.Gender{model.GenderFemale}}),
+			},
+			matches: People{
+				withPreferences(createPerson("3", model.GenderMale, 14, 1), &model.Preferences{Seeking: []model.Gender{model.GenderFemale}}),
+			},
+			match: withPreferences(createPerson("3", model.GenderMale, 14, 0), &model.Preferences{Seeking: []model.Gender{model.GenderFemale}}),
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend, test.people...)
+				got, _, gotErr := s.PossibleMatches("1", nil, 5)
+				if diff := cmp.Diff(got.People(), test.matches); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if got, want := gotErr, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+				match, gotErr := s.Match("1")
+				if diff := cmp.Diff(match, test.match); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if got, want := gotErr, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestPossibleMatchesWithGenders(t *testing.T) {
+	const genderFluid model.Gender = "genderfluid"
+	people := People{
+		withPreferences(createPerson("1", model.GenderNonBinary, 10, 1), &model.Preferences{Seeking: []model.Gender{model.GenderFemale, model.GenderMale}}),
+		createPerson("2", model.GenderFemale, 9, 1),
+		createPerson("3", model.GenderMale, 12, 1),
+		createPerson("4", model.GenderNonBinary, 11, 1),
+		withPreferences(createPerson("5", genderFluid, 8, 1), &model.Preferences{Seeking: []model.Gender{genderFluid, model.GenderNonBinary}}),
+		withPreferences(createPerson("6", model.GenderMale, 13, 1), &model.Preferences{Seeking: []model.Gender{model.GenderNonBinary}}),
+		withPreferences(createPerson("7", model.GenderNonBinary, 7, 1), &model.Preferences{Seeking: []model.Gender{genderFluid}}),
+		withPreferences(createPerson("8", model.GenderMale, 15, 1), &model.Preferences{Seeking: []model.Gender{model.GenderMale}}),
+	}
+	tests := []struct {
+		name    string
+		id      string
+		matches People
+		err     error
+	}{
+		{
+			// 2 and 3 seek no gender in particular, the default rules only
+			// look for the other one of female and male.
+			name: "non_binary_seeking_female_and_male",
+			id:   "1",
+			matches: People{
+				people[5],
+			},
+		},
+		{
+			name: "no_rule_and_not_seeking",
+			id:   "4",
+			err:  ErrNotFound,
+		},
+		{
+			name: "custom_gender",
+			id:   "5",
+			matches: People{
+				people[6],
+			},
+		},
+		{
+			name: "male_seeking_non_binary",
+			id:   "6",
+			matches: People{
+				people[0],
+			},
+		},
+		{
+			// 3 seeks no gender in particular and the default rules of males look for females.
+			name: "male_seeking_male",
+			id:   "8",
+			err:  ErrNotFound,
+		},
+		{
+			name: "default_rules",
+			id:   "2",
+			matches: People{
+				people[2],
+			},
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend, people...)
+				got, _, gotErr := s.PossibleMatches(test.id, nil, 5)
+				if diff := cmp.Diff(got.People(), test.matches); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if got, want := gotErr, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestPossibleMatchesWithAge(t *testing.T) {
+	tests := []struct {
+		name        string
+		birthDate   string
+		preferences *model.Preferences
+		rules       Rules
+		matches     []string
+		err         error
+	}{
+		{
+			name:      "no_age_constraint",
+			birthDate: "1994-05-01",
+			rules:     DefaultRules(),
+			matches:   []string{"2", "5", "4"},
+		},
+		{
+			name:        "preferred_age",
+			birthDate:   "1994-05-01",
+			preferences: &model.Preferences{Age: &model.AgeRange{Min: 25, Max: 35}},
+			rules:       DefaultRules(),
+			matches:     []string{"5", "2"},
+		},
+		{
+			name:        "preferred_age_boundary",
+			birthDate:   "1994-05-01",
+			preferences: &model.Preferences{Age: &model.AgeRange{Min: 29, Max: 29}},
+			rules:       DefaultRules(),
+			matches:     []string{"5"},
+		},
+		{
+			name:      "rule_age_diff",
+			birthDate: "1994-05-01",
+			rules: Rules{Rules: []Rule{
+				{Gender: model.GenderFemale, CandidateGender: model.GenderMale, MinHeightDiff: intPtr(1), MinAgeDiff: intPtr(0), MaxAgeDiff: intPtr(5)},
+				{Gender: model.GenderMale, CandidateGender: model.GenderFemale},
+			}},
+			matches: []string{"2"},
+		},
+		{
+			name:        "candidate_preferred_age",
+			birthDate:   "2004-01-01",
+			preferences: &model.Preferences{Age: &model.AgeRange{Min: 18, Max: 99}},
+			rules:       DefaultRules(),
+			matches:     []string{"2", "3", "5"},
+		},
+		{
+			name: "rule_age_diff_unknown_age",
+			rules: Rules{Rules: []Rule{
+				{Gender: model.GenderFemale, CandidateGender: model.GenderMale, MinAgeDiff: intPtr(0)},
+			}},
+			err: ErrNotFound,
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				person := withPreferences(createPerson("1", model.GenderFemale, 10, 1), test.preferences)
+				person.BirthDate = test.birthDate
+				s := setupTestWithOptions(t, backend, []Option{WithRules(test.rules), WithClock(func() time.Time { return testTime })},
+					person,
+					withBirthDate(createPerson("2", model.GenderMale, 12, 1), "1990-06-01"),
+					withBirthDate(withPreferences(createPerson("3", model.GenderMale, 14, 1), &model.Preferences{Age: &model.AgeRange{Min: 18, Max: 25}}), "1999-05-02"),
+					createPerson("4", model.GenderMale, 16, 1),
+					withBirthDate(createPerson("5", model.GenderMale, 13, 1), "1994-05-02"),
+				)
+				got, _, gotErr := s.PossibleMatches("1", nil, 5)
+				var ids []string
+				for _, match := range got {
+					ids = append(ids, match.ID)
+				}
+				if diff := cmp.Diff(ids, test.matches); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				if got, want := gotErr, test.err; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+			})
+		}
+	}
+}
+
+func withBirthDate(person *Person, birthDate string) *Person {
+	person.BirthDate = birthDate
+	return person
+}
+
+func withPreferences(person *Person, preferences *model.Preferences) *Person {
+	person.Preferences = preferences
+	return person
+}
@@ -0,0 +1,329 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/bito_interview/model"
+)
+
+// Order is the order of the possible matches, by height then id or by score.
+type Order string
+
+const (
+	OrderAscending  Order = "asc"
+	OrderDescending Order = "desc"
+	// OrderScore ranks the possible matches by compatibility score, the
+	// highest first, which scores every candidate allowed by the rules.
+	OrderScore Order = "score"
+)
+
+// Rule allows a person of Gender to match candidates of CandidateGender
+// whose height minus the person's height is between MinHeightDiff and
+// MaxHeightDiff inclusively, and likewise for the age with MinAgeDiff and
+// MaxAgeDiff. A missing bound leaves that side open. A rule bounding the age
+// only matches people whose birth dates are known.
+type Rule struct {
+	Gender          model.Gender `json:"gender"`
+	CandidateGender model.Gender `json:"candidate_gender"`
+	MinHeightDiff   *int         `json:"min_height_diff,omitempty"`
+	MaxHeightDiff   *int         `json:"max_height_diff,omitempty"`
+	MinAgeDiff      *int         `json:"min_age_diff,omitempty"`
+	MaxAgeDiff      *int         `json:"max_age_diff,omitempty"`
+}
+
+// Rules are the matching rules of a store and the order of their candidates,
+// ascending when Order is empty. The score of the candidates is weighed by
+// Weights, or DefaultScoreWeights when it is nil.
+type Rules struct {
+	Rules   []Rule        `json:"rules"`
+	Order   Order         `json:"order"`
+	Weights *ScoreWeights `json:"weights,omitempty"`
+}
+
+// DefaultRules matches females with taller males and males with shorter
+// females, the candidates with the highest score first.
+func DefaultRules() Rules {
+	return Rules{
+		Rules: []Rule{
+			{Gender: model.GenderFemale, CandidateGender: model.GenderMale, MinHeightDiff: intPtr(1)},
+			{Gender: model.GenderMale, CandidateGender: model.GenderFemale, MaxHeightDiff: intPtr(-1)},
+		},
+		Order: OrderScore,
+	}
+}
+
+// LoadRules reads the rules from a JSON file, ranking the candidates by
+// score when the file sets no order.
+func LoadRules(path string) (Rules, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Rules{}, err
+	}
+	defer file.Close()
+	// a misspelled field fails at startup rather than being silently ignored.
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	rules := Rules{}
+	if err := decoder.Decode(&rules); err != nil {
+		return Rules{}, fmt.Errorf("parse rules %s: %w", path, err)
+	}
+	if rules.Order == "" {
+		rules.Order = OrderScore
+	}
+	if err := rules.Validate(); err != nil {
+		return Rules{}, fmt.Errorf("invalid rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Validate checks the order, the weights and that every pair of genders has at most one rule.
+func (r Rules) Validate() error {
+	if r.Order != OrderAscending && r.Order != OrderDescending && r.Order != OrderScore {
+		return fmt.Errorf("unknown order %q", r.Order)
+	}
+	if r.Weights != nil {
+		if err := r.Weights.Validate(); err != nil {
+			return err
+		}
+	}
+	seen := map[[2]model.Gender]bool{}
+	for _, rule := range r.Rules {
+		if rule.Gender == "" || rule.CandidateGender == "" {
+			return errors.New("gender and candidate_gender are required")
+		}
+		if rule.MinHeightDiff != nil && rule.MaxHeightDiff != nil && *rule.MinHeightDiff > *rule.MaxHeightDiff {
+			return fmt.Errorf("rule %s to %s: min_height_diff is greater than max_height_diff", rule.Gender, rule.CandidateGender)
+		}
+		if rule.MinAgeDiff != nil && rule.MaxAgeDiff != nil && *rule.MinAgeDiff > *rule.MaxAgeDiff {
+			return fmt.Errorf("rule %s to %s: min_age_diff is greater than max_age_diff", rule.Gender, rule.CandidateGender)
+		}
+		pair := [2]model.Gender{rule.Gender, rule.CandidateGender}
+		if seen[pair] {
+			return fmt.Errorf("duplicated rule %s to %s", rule.Gender, rule.CandidateGender)
+		}
+		seen[pair] = true
+	}
+	return nil
+}
+
+func (r Rules) weights() ScoreWeights {
+	if r.Weights == nil {
+		return DefaultScoreWeights()
+	}
+	return *r.Weights
+}
+
+// For returns the rules applying to a person of the given gender.
+func (r Rules) For(gender model.Gender) []Rule {
+	var rules []Rule
+	for _, rule := range r.Rules {
+		if rule.Gender == gender {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// seeks reports whether the person wants a partner of the gender. A person
+// seeking no gender in particular wants the candidate genders of the rules
+// of his/her gender.
+func (r Rules) seeks(person *Person, gender model.Gender) bool {
+	if person.Preferences != nil && len(person.Preferences.Seeking) > 0 {
+		return person.Seeks(gender)
+	}
+	for _, rule := range r.For(person.Gender) {
+		if rule.CandidateGender == gender {
+			return true
+		}
+	}
+	return false
+}
+
+// seekers returns the genders whose rules look for candidates of the gender.
+func (r Rules) seekers(gender model.Gender) []model.Gender {
+	seekers := []model.Gender{}
+	for _, rule := range r.Rules {
+		if rule.CandidateGender == gender {
+			seekers = append(seekers, rule.Gender)
+		}
+	}
+	return seekers
+}
+
+// searches returns a rule for every gender sought by the person, a gender
+// without rule is not restricted by height. A person seeking no gender in
+// particular looks up the candidates of the rules of his/her gender.
+func (r Rules) searches(person *Person) []Rule {
+	if person.Preferences == nil || len(person.Preferences.Seeking) == 0 {
+		return r.For(person.Gender)
+	}
+	rules := make([]Rule, 0, len(person.Preferences.Seeking))
+	for _, gender := range person.Preferences.Seeking {
+		rule := Rule{Gender: person.Gender, CandidateGender: gender}
+		for _, candidate := range r.Rules {
+			if candidate.Gender == person.Gender && candidate.CandidateGender == gender {
+				rule = candidate
+				break
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// Allows reports whether the rule lets the person match the candidate at the given time.
+func (r Rule) Allows(person *Person, candidate *Person, now time.Time) bool {
+	if person.Gender != r.Gender || candidate.Gender != r.CandidateGender || person.ID == candidate.ID {
+		return false
+	}
+	low, high := r.heightRange(person)
+	return low <= candidate.Height && candidate.Height <= high && r.ageRange(person, now).allows(candidate, now)
+}
+
+// heightRange returns the inclusive height range of the candidates of the person.
+func (r Rule) heightRange(person *Person) (int, int) {
+	low, high := math.MinInt, math.MaxInt
+	if r.MinHeightDiff != nil {
+		low = person.Height + *r.MinHeightDiff
+	}
+	if r.MaxHeightDiff != nil {
+		high = person.Height + *r.MaxHeightDiff
+	}
+	return low, high
+}
+
+// searchRange narrows down the height range of the rule to the height the person prefers.
+func (r Rule) searchRange(person *Person) (int, int) {
+	low, high := r.heightRange(person)
+	if person.Preferences != nil && person.Preferences.Height != nil {
+		low = max(low, person.Preferences.Height.Min)
+		high = min(high, person.Preferences.Height.Max)
+	}
+	return low, high
+}
+
+// ageRange is an inclusive range of ages, any age including an unknown one
+// is allowed when it is not constrained.
+type ageRange struct {
+	low         int
+	high        int
+	constrained bool
+}
+
+func (a ageRange) allows(candidate *Person, now time.Time) bool {
+	if !a.constrained {
+		return true
+	}
+	age, ok := candidate.Age(now)
+	return ok && a.low <= age && age <= a.high
+}
+
+// birthYears returns the range of the birth years of the ages.
+func (a ageRange) birthYears(now time.Time) (int, int) {
+	from, to := math.MinInt, math.MaxInt
+	if a.high != math.MaxInt {
+		from = now.AddDate(-a.high-1, 0, 1).Year()
+	}
+	if a.low != math.MinInt {
+		to = now.AddDate(-a.low, 0, 0).Year()
+	}
+	return from, to
+}
+
+// birthDates returns the inclusive range of the birth dates of the ages,
+// an open side is left empty.
+func (a ageRange) birthDates(now time.Time) (string, string) {
+	var from, to string
+	if a.high != math.MaxInt {
+		from = now.AddDate(-a.high-1, 0, 1).Format(model.DateLayout)
+	}
+	if a.low != math.MinInt {
+		to = now.AddDate(-a.low, 0, 0).Format(model.DateLayout)
+	}
+	return from, to
+}
+
+// ageRange returns the age range of the candidates of the person under the
+// rule and the age the person prefers.
+func (r Rule) ageRange(person *Person, now time.Time) ageRange {
+	ages := ageRange{low: math.MinInt, high: math.MaxInt}
+	if r.MinAgeDiff != nil || r.MaxAgeDiff != nil {
+		ages.constrained = true
+		age, ok := person.Age(now)
+		if !ok {
+			return ageRange{low: 1, high: 0, constrained: true}
+		}
+		if r.MinAgeDiff != nil {
+			ages.low = age + *r.MinAgeDiff
+		}
+		if r.MaxAgeDiff != nil {
+			ages.high = age + *r.MaxAgeDiff
+		}
+	}
+	if person.Preferences != nil && person.Preferences.Age != nil {
+		ages.constrained = true
+		ages.low = max(ages.low, person.Preferences.Age.Min)
+		ages.high = min(ages.high, person.Preferences.Age.Max)
+	}
+	return ages
+}
+
+// candidates returns the parts of the gender index allowed by the rule and
+// the height and the birth years the person prefers.
+func (r Rule) candidates(person *Person, index *genderIndex, now time.Time) []People {
+	low, high := r.searchRange(person)
+	ages := r.ageRange(person, now)
+	if ages.low > ages.high {
+		return nil
+	}
+	fromYear, toYear := ages.birthYears(now)
+	return index.ranges(low, high, fromYear, toYear, !ages.constrained)
+}
+
+// walk visits the people of the height sorted slices merged in the given
+// order until visit returns false.
+func walk(ranges []People, order Order, visit func(person *Person) bool) {
+	heads := make([]int, len(ranges))
+	if order == OrderDescending {
+		for i, people := range ranges {
+			heads[i] = len(people) - 1
+		}
+	}
+	for {
+		next := -1
+		for i, people := range ranges {
+			if heads[i] < 0 || heads[i] >= len(people) {
+				continue
+			}
+			if next < 0 {
+				next = i
+				continue
+			}
+			cmp := heightCmp(people[heads[i]], ranges[next][heads[next]])
+			if (order == OrderDescending && cmp > 0) || (order != OrderDescending && cmp < 0) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return
+		}
+		person := ranges[next][heads[next]]
+		if order == OrderDescending {
+			heads[next]--
+		} else {
+			heads[next]++
+		}
+		if !visit(person) {
+			return
+		}
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
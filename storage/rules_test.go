@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bito_interview/model"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		rules   Rules
+		wantErr bool
+	}{
+		{
+			name:    "default_order",
+			content: `{"rules":[{"gender":"female","candidate_gender":"male","min_height_diff":0,"max_height_diff":20}]}`,
+			rules: Rules{
+				Rules: []Rule{{Gender: model.GenderFemale, CandidateGender: model.GenderMale, MinHeightDiff: intPtr(0), MaxHeightDiff: intPtr(20)}},
+				Order: OrderScore,
+			},
+		},
+		{
+			name:    "descending",
+			content: `{"rules":[{"gender":"male","candidate_gender":"female"}],"order":"desc"}`,
+			rules: Rules{
+				Rules: []Rule{{Gender: model.GenderMale, CandidateGender: model.GenderFemale}},
+				Order: OrderDescending,
+			},
+		},
+		{
+			name:    "score_order",
+			content: `{"rules":[{"gender":"male","candidate_gender":"female"}],"order":"score","weights":{"height":2,"activity":1}}`,
+			rules: Rules{
+				Rules:   []Rule{{Gender: model.GenderMale, CandidateGender: model.GenderFemale}},
+				Order:   OrderScore,
+				Weights: &ScoreWeights{Height: 2, Activity: 1},
+			},
+		},
+		{
+			name:    "negative_weight",
+			content: `{"rules":[],"order":"score","weights":{"height":-1,"activity":1}}`,
+			wantErr: true,
+		},
+		{
+			name:    "zero_weights",
+			content: `{"rules":[],"order":"score","weights":{}}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown_order",
+			content: `{"rules":[],"order":"random"}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing_gender",
+			content: `{"rules":[{"gender":"male"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "min_greater_than_max",
+			content: `{"rules":[{"gender":"male","candidate_gender":"female","min_height_diff":1,"max_height_diff":-1}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "duplicated_rule",
+			content: `{"rules":[{"gender":"male","candidate_gender":"female"},{"gender":"male","candidate_gender":"female"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown_field",
+			content: `{"rules":[{"gender":"male","candidate_gender":"female","max_height_dif":10}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid_json",
+			content: `{"rules":`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "rules.json")
+			if err := os.WriteFile(path, []byte(test.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadRules(path)
+			if got, want := err != nil, test.wantErr; got != want {
+				t.Fatalf("%s got error %v but want error: %v", t.Name(), err, want)
+			}
+			if diff := cmp.Diff(got, test.rules); !test.wantErr && diff != "" {
+				t.Errorf("%s got want:\n%s", t.Name(), diff)
+			}
+		})
+	}
+}
+
+func TestPossibleMatchesWithRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   Rules
+		matches People
+	}{
+		// the rules of the candidates look for females, so that the males accept person 1.
+		{
+			name:  "default",
+			rules: DefaultRules(),
+			matches: People{
+				createPerson("4", model.GenderMale, 12, 1),
+				createPerson("5", model.GenderMale, 16, 1),
+			},
+		},
+		{
+			name: "same_height_allowed",
+			rules: Rules{Rules: []Rule{
+				{Gender: model.GenderFemale, CandidateGender: model.GenderMale, MinHeightDiff: intPtr(0)},
+				{Gender: model.GenderMale, CandidateGender: model.GenderFemale},
+			}},
+			matches: People{
+				createPerson("3", model.GenderMale, 10, 1),
+				createPerson("4", model.GenderMale, 12, 1),
+				createPerson("5", model.GenderMale, 16, 1),
+			},
+		},
+		{
+			name: "max_height_diff",
+			rules: Rules{Rules: []Rule{
+				{Gender: model.GenderFemale, CandidateGender: model.GenderMale, MinHeightDiff: intPtr(1), MaxHeightDiff: intPtr(5)},
+				{Gender: model.GenderMale, CandidateGender: model.GenderFemale},
+			}},
+			matches: People{
+				createPerson("4", model.GenderMale, 12, 1),
+			},
+		},
+		{
+			name: "reversed",
+			rules: Rules{Rules: []Rule{
+				{Gender: model.GenderFemale, CandidateGender: model.GenderMale, MaxHeightDiff: intPtr(-1)},
+				{Gender: model.GenderMale, CandidateGender: model.GenderFemale},
+			}},
+			matches: People{
+				createPerson("2", model.GenderMale, 9, 1),
+			},
+		},
+		{
+			name:  "descending",
+			rules: Rules{Rules: DefaultRules().Rules, Order: OrderDescending},
+			matches: People{
+				createPerson("5", model.GenderMale, 16, 1),
+				createPerson("4", model.GenderMale, 12, 1),
+			},
+		},
+		{
+			name: "many_genders_merged",
+			rules: Rules{Rules: []Rule{
+				{Gender: model.GenderFemale, CandidateGender: model.GenderMale, MinHeightDiff: intPtr(0)},
+				{Gender: model.GenderFemale, CandidateGender: model.GenderFemale},
+				{Gender: model.GenderMale, CandidateGender: model.GenderFemale},
+			}},
+			matches: People{
+				createPerson("6", model.GenderFemale, 8, 1),
+				createPerson("3", model.GenderMale, 10, 1),
+				createPerson("4", model.GenderMale, 12, 1),
+				createPerson("5", model.GenderMale, 16, 1),
+			},
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTestWithOptions(t, backend, []Option{WithRules(test.rules)},
+					createPerson("1", model.GenderFemale, 10, 1),
+					createPerson("2", model.GenderMale, 9, 1),
+					createPerson("3", model.GenderMale, 10, 1),
+					createPerson("4", model.GenderMale, 12, 1),
+					createPerson("5", model.GenderMale, 16, 1),
+					createPerson("6", model.GenderFemale, 8, 1),
+				)
+				got, _, err := s.PossibleMatches("1", nil, 5)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(got.People(), test.matches); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/bito_interview/model"
+)
+
+const (
+	// heightScale is the height gap halving the height component about every 7cm.
+	heightScale = 10.0
+	// distanceScale is the distance halving the distance component about every 35km.
+	distanceScale = 50.0
+	// activityHalfLife is the time since the last activity halving the activity component.
+	activityHalfLife = 7 * 24 * time.Hour
+)
+
+// ScoreWeights weigh the components of the compatibility score of a
+// candidate, every component is between 0 and 1:
+//   - Height is higher for a smaller height gap between the two people.
+//   - Preferences is higher the more central each person is in the ranges
+//     preferred by the other one, 1 when no range is preferred.
+//   - Distance is higher for closer people, 0 when a location is unknown.
+//   - Activity is higher for a candidate added or matched more recently.
+type ScoreWeights struct {
+	Height      float64 `json:"height"`
+	Preferences float64 `json:"preferences"`
+	Distance    float64 `json:"distance"`
+	Activity    float64 `json:"activity"`
+}
+
+// DefaultScoreWeights weigh every component of the score equally.
+func DefaultScoreWeights() ScoreWeights {
+	return ScoreWeights{Height: 1, Preferences: 1, Distance: 1, Activity: 1}
+}
+
+// Validate checks that no weight is negative and some weight is positive.
+func (w ScoreWeights) Validate() error {
+	if w.Height < 0 || w.Preferences < 0 || w.Distance < 0 || w.Activity < 0 {
+		return errors.New("negative score weight")
+	}
+	if w.Height+w.Preferences+w.Distance+w.Activity == 0 {
+		return errors.New("every score weight is 0")
+	}
+	return nil
+}
+
+// score returns the weighted average of the components of the score of the
+// candidate for the person, activeAt is zero when the last activity of the
+// candidate is unknown.
+func (w ScoreWeights) score(person *Person, candidate *Person, distanceKm *float64, activeAt time.Time, now time.Time) float64 {
+	score := w.Height * math.Exp(-math.Abs(float64(candidate.Height-person.Height))/heightScale)
+	score += w.Preferences * preferencesFit(person, candidate, distanceKm, now)
+	if distanceKm != nil {
+		score += w.Distance * math.Exp(-*distanceKm/distanceScale)
+	}
+	if !activeAt.IsZero() {
+		score += w.Activity * math.Exp2(-float64(now.Sub(activeAt))/float64(activityHalfLife))
+	}
+	return score / (w.Height + w.Preferences + w.Distance + w.Activity)
+}
+
+// preferencesFit averages how central both people are in the ranges
+// preferred by each other.
+func preferencesFit(person *Person, candidate *Person, distanceKm *float64, now time.Time) float64 {
+	var sum float64
+	var count int
+	fit := func(preferring *Person, other *Person) {
+		preferences := preferring.Preferences
+		if preferences == nil {
+			return
+		}
+		if height := preferences.Height; height != nil {
+			sum += centrality(float64(other.Height), float64(height.Min), float64(height.Max))
+			count++
+		}
+		if ages := preferences.Age; ages != nil {
+			if age, ok := other.Age(now); ok {
+				sum += centrality(float64(age), float64(ages.Min), float64(ages.Max))
+				count++
+			}
+		}
+		if limitKm := preferences.MaxDistanceKm; limitKm != nil && distanceKm != nil {
+			sum += max(0, 1-*distanceKm/(*limitKm))
+			count++
+		}
+	}
+	fit(person, candidate)
+	fit(candidate, person)
+	if count == 0 {
+		return 1
+	}
+	return sum / float64(count)
+}
+
+// centrality is 1 in the middle of the inclusive range and 0 at its bounds.
+func centrality(value float64, low float64, high float64) float64 {
+	if low == high {
+		return 1
+	}
+	half := (high - low) / 2
+	return max(0, 1-math.Abs(value-low-half)/half)
+}
+
+// Candidate is a possible match with its distance in kilometers from the
+// person, nil when the location of either of them is unknown, and its
+// compatibility score. ActiveAt is the last time the candidate was added or
+// matched, zero when it is unknown.
+type Candidate struct {
+	*Person
+	DistanceKm *float64  `json:"distance_km,omitempty"`
+	Score      float64   `json:"score"`
+	ActiveAt   time.Time `json:"-"`
+}
+
+type Candidates []Candidate
+
+// People returns the possible matches without their distances and scores.
+func (c Candidates) People() People {
+	if c == nil {
+		return nil
+	}
+	people := make(People, 0, len(c))
+	for _, candidate := range c {
+		people = append(people, candidate.Person)
+	}
+	return people
+}
+
+// candidatesOf scores the possible matches of the person and keeps the
+// first n. When the rules order the candidates by score, the best n after
+// the cursor are kept, highest score first and in height then id order
+// among equal scores. The cursor of the next page is returned when more
+// matches than n were looked up.
+func (r Rules) candidatesOf(person *Person, matches People, after *Cursor, n int, activeAt func(id string) time.Time, now time.Time) (Candidates, *Cursor) {
+	weights := r.weights()
+	candidates := make(Candidates, 0, len(matches))
+	for _, match := range matches {
+		candidate := Candidate{Person: match}
+		if person.Location != nil && match.Location != nil {
+			distance := model.DistanceKm(person.Location, match.Location)
+			candidate.DistanceKm = &distance
+		}
+		candidate.ActiveAt = activeAt(match.ID)
+		candidate.Score = weights.score(person, match, candidate.DistanceKm, candidate.ActiveAt, now)
+		candidates = append(candidates, candidate)
+	}
+	if r.Order == OrderScore {
+		slices.SortFunc(candidates, scoreCmp)
+		if after != nil {
+			index, found := slices.BinarySearchFunc(candidates, after.candidate(), scoreCmp)
+			if found {
+				index++
+			}
+			candidates = candidates[index:]
+		}
+	}
+	var next *Cursor
+	if len(candidates) > n {
+		candidates = candidates[:n]
+		next = cursorOf(candidates[n-1].Person)
+		if r.Order == OrderScore {
+			next.Score = &candidates[n-1].Score
+		}
+	}
+	return candidates, next
+}
+
+// scoreCmp orders the candidates by score, the highest first, then by height and id.
+func scoreCmp(c1 Candidate, c2 Candidate) int {
+	switch {
+	case c1.Score > c2.Score:
+		return -1
+	case c1.Score < c2.Score:
+		return 1
+	}
+	return heightCmp(c1.Person, c2.Person)
+}
+
+// limit is the number of candidates to look up for n possible matches, one
+// more than n telling whether there is a next page, or all of them when they
+// are ordered by score since the best ones may be anywhere.
+func (r Rules) limit(n int) int {
+	if r.Order == OrderScore || n == math.MaxInt {
+		return math.MaxInt
+	}
+	return n + 1
+}
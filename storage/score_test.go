@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bito_interview/model"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPossibleMatchesByScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights ScoreWeights
+		people  People
+		n       int
+		matches []string
+	}{
+		{
+			name:    "height",
+			weights: ScoreWeights{Height: 1},
+			people: People{
+				createPerson("1", model.GenderFemale, 10, 1),
+				createPerson("2", model.GenderMale, 20, 1),
+				createPerson("3", model.GenderMale, 12, 1),
+				createPerson("4", model.GenderMale, 15, 1),
+			},
+			n:       5,
+			matches: []string{"3", "4", "2"},
+		},
+		{
+			name:    "top_n",
+			weights: ScoreWeights{Height: 1},
+			people: People{
+				createPerson("1", model.GenderFemale, 10, 1),
+				createPerson("2", model.GenderMale, 20, 1),
+				createPerson("3", model.GenderMale, 12, 1),
+				createPerson("4", model.GenderMale, 15, 1),
+			},
+			n:       2,
+			matches: []string{"3", "4"},
+		},
+		{
+			name:    "preferences",
+			weights: ScoreWeights{Preferences: 1},
+			people: People{
+				withPreferences(createPerson("1", model.GenderFemale, 10, 1), &model.Preferences{Height: &model.HeightRange{Min: 10, Max: 20}}),
+				createPerson("2", model.GenderMale, 19, 1),
+				createPerson("3", model.GenderMale, 15, 1),
+				createPerson("4", model.GenderMale, 12, 1),
+			},
+			n:       5,
+			matches: []string{"3", "4", "2"},
+		},
+		{
+			name:    "distance",
+			weights: ScoreWeights{Distance: 1},
+			people: People{
+				withLocation(createPerson("1", model.GenderFemale, 10, 1), taipei),
+				withLocation(createPerson("2", model.GenderMale, 11, 1), taichung),
+				withLocation(createPerson("3", model.GenderMale, 12, 1), taoyuan),
+				createPerson("4", model.GenderMale, 13, 1),
+			},
+			n:       5,
+			matches: []string{"3", "2", "4"},
+		},
+		{
+			name:    "activity",
+			weights: ScoreWeights{Activity: 1},
+			people: People{
+				createPerson("1", model.GenderFemale, 10, 1),
+				createPerson("2", model.GenderMale, 11, 1),
+				createPerson("3", model.GenderMale, 12, 1),
+				createPerson("4", model.GenderMale, 13, 1),
+			},
+			n:       5,
+			matches: []string{"4", "3", "2"},
+		},
+		{
+			name:    "equal_scores",
+			weights: ScoreWeights{Preferences: 1},
+			people: People{
+				createPerson("1", model.GenderFemale, 10, 1),
+				createPerson("2", model.GenderMale, 13, 1),
+				createPerson("3", model.GenderMale, 12, 1),
+				createPerson("4", model.GenderMale, 11, 1),
+			},
+			n:       5,
+			matches: []string{"4", "3", "2"},
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				rules := DefaultRules()
+				rules.Order = OrderScore
+				rules.Weights = &test.weights
+				now := testTime
+				s := backend.new(t, WithRules(rules), WithClock(func() time.Time { return now }))
+				// everyone is added a day after the previous one.
+				for _, person := range test.people {
+					if _, err := s.Add(person.ID, &person.Person); err != nil {
+						t.Fatal(err)
+					}
+					now = now.Add(24 * time.Hour)
+				}
+				got, _, err := s.PossibleMatches("1", nil, test.n)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var ids []string
+				for i, match := range got {
+					ids = append(ids, match.ID)
+					if match.Score < 0 || match.Score > 1 || (i > 0 && match.Score > got[i-1].Score) {
+						t.Errorf("%s got score %v of %v after %v", t.Name(), match.Score, match.ID, got[max(i-1, 0)].Score)
+					}
+				}
+				if diff := cmp.Diff(ids, test.matches); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+			})
+		}
+	}
+}
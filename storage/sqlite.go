@@ -0,0 +1,888 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bito_interview/model"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteMigrations are applied in order, PRAGMA user_version is the number
+// of migrations already applied to the database.
+var sqliteMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS people (
+		id                     TEXT PRIMARY KEY,
+		name                   TEXT NOT NULL,
+		height                 INTEGER NOT NULL,
+		gender                 TEXT NOT NULL,
+		number_of_wanted_dates INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS people_gender_height_id ON people (gender, height, id);
+	CREATE TABLE IF NOT EXISTS matches (
+		id                TEXT PRIMARY KEY,
+		person_a          TEXT NOT NULL,
+		person_b          TEXT NOT NULL,
+		matched_at        INTEGER NOT NULL,
+		remaining_dates_a INTEGER NOT NULL,
+		remaining_dates_b INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS matches_person_a ON matches (person_a);
+	CREATE INDEX IF NOT EXISTS matches_person_b ON matches (person_b);`,
+	`ALTER TABLE people ADD COLUMN preferred_height_min INTEGER;
+	ALTER TABLE people ADD COLUMN preferred_height_max INTEGER;
+	ALTER TABLE people ADD COLUMN seeking TEXT;`,
+	`ALTER TABLE people ADD COLUMN birth_date TEXT;
+	ALTER TABLE people ADD COLUMN preferred_age_min INTEGER;
+	ALTER TABLE people ADD COLUMN preferred_age_max INTEGER;
+	CREATE INDEX IF NOT EXISTS people_gender_birth_date_height ON people (gender, birth_date, height);`,
+	`ALTER TABLE people ADD COLUMN latitude REAL;
+	ALTER TABLE people ADD COLUMN longitude REAL;
+	ALTER TABLE people ADD COLUMN max_distance_km REAL;
+	CREATE INDEX IF NOT EXISTS people_latitude_longitude ON people (latitude, longitude);`,
+	`ALTER TABLE people ADD COLUMN active_at INTEGER;`,
+	`CREATE TABLE IF NOT EXISTS swipes (
+		person TEXT NOT NULL,
+		target TEXT NOT NULL,
+		liked  INTEGER NOT NULL,
+		PRIMARY KEY (person, target)
+	);
+	CREATE INDEX IF NOT EXISTS swipes_target ON swipes (target);`,
+	`CREATE TABLE IF NOT EXISTS blocks (
+		person TEXT NOT NULL,
+		target TEXT NOT NULL,
+		PRIMARY KEY (person, target)
+	);
+	CREATE INDEX IF NOT EXISTS blocks_target ON blocks (target);
+	CREATE TABLE IF NOT EXISTS reports (
+		id          TEXT PRIMARY KEY,
+		reporter    TEXT NOT NULL,
+		target      TEXT NOT NULL,
+		reason      TEXT NOT NULL,
+		comment     TEXT NOT NULL,
+		reported_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS reports_reason ON reports (reason);
+	CREATE INDEX IF NOT EXISTS reports_target ON reports (target);`,
+	`CREATE INDEX IF NOT EXISTS people_height_id ON people (height, id);`,
+}
+
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	for ; version < len(sqliteMigrations); version++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(sqliteMigrations[version]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", version+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, version+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notMatchedWith filters out the people already matched to the person whose
+// id is bound twice.
+const notMatchedWith = `NOT EXISTS (SELECT 1 FROM matches
+	WHERE (person_a = ? AND person_b = people.id) OR (person_b = ? AND person_a = people.id))`
+
+// notPassedBy filters out the people passed on by the person whose id is bound.
+const notPassedBy = `NOT EXISTS (SELECT 1 FROM swipes WHERE person = ? AND target = people.id AND liked = 0)`
+
+// notBlocked filters out the people blocked by or blocking the person whose id is bound twice.
+const notBlocked = `NOT EXISTS (SELECT 1 FROM blocks
+	WHERE (person = ? AND target = people.id) OR (person = people.id AND target = ?))`
+
+// selectedBy filters the people selected by a PeopleFilter whose fields
+// are bound by peopleFilterArgs.
+const selectedBy = `(? = '' OR gender = ?) AND (? = 0 OR height >= ?) AND (? = 0 OR height <= ?)
+	AND (? = 0 OR number_of_wanted_dates >= ?) AND (? = 0 OR number_of_wanted_dates <= ?)
+	AND substr(name, 1, length(?)) = ?`
+
+func peopleFilterArgs(f PeopleFilter) []any {
+	return []any{f.Gender, f.Gender, f.MinHeight, f.MinHeight, f.MaxHeight, f.MaxHeight,
+		f.MinDates, f.MinDates, f.MaxDates, f.MaxDates, f.NamePrefix, f.NamePrefix}
+}
+
+// acceptsPerson filters out the people whose preferences do not accept the
+// person whose height, age, gender and the JSON array of the genders whose
+// rules look for his/her gender are bound. The people seeking no gender in
+// particular only accept the genders of the rules of their gender.
+const acceptsPerson = `(preferred_height_min IS NULL OR ? BETWEEN preferred_height_min AND preferred_height_max)
+	AND (preferred_age_min IS NULL OR ? BETWEEN preferred_age_min AND preferred_age_max)
+	AND ((seeking IS NULL AND gender IN (SELECT value FROM json_each(?)))
+		OR EXISTS (SELECT 1 FROM json_each(seeking) WHERE value = ?))`
+
+const (
+	personColumns = `id, name, height, gender, number_of_wanted_dates, birth_date, latitude, longitude,
+		preferred_height_min, preferred_height_max, preferred_age_min, preferred_age_max, seeking, max_distance_km`
+	matchColumns  = `id, person_a, person_b, matched_at, remaining_dates_a, remaining_dates_b`
+	reportColumns = `id, reporter, target, reason, comment, reported_at`
+)
+
+// SQLiteStore keeps the candidate pool in a SQLite database. The
+// (gender, height, id) index turns the possible matches lookups into range
+// queries in the same order as the in-memory height index.
+type SQLiteStore struct {
+	db *sql.DB
+	// readDB reads point-in-time views of the database in WAL mode, next to
+	// the writers rather than on their only connection.
+	readDB *sql.DB
+	// publishing holds the writers publishing domain events from the start of
+	// their transaction until the events are queued, so that the events are
+	// published in the order of the transactions.
+	publishing sync.Mutex
+	options
+}
+
+var _ Store = (*SQLiteStore)(nil)
+
+// NewSQLiteStore opens the SQLite database at path, creating it when it does not exist.
+func NewSQLiteStore(path string, opts ...Option) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_txlock=immediate&_busy_timeout=5000&_journal_mode=WAL", path))
+	if err != nil {
+		return nil, err
+	}
+	// a single connection serializes the writers like the lock of MemoryStore.
+	db.SetMaxOpenConns(1)
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	readDB, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_txlock=deferred&_busy_timeout=5000&_query_only=true", path))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{db: db, readDB: readDB, options: newOptions(opts...)}, nil
+}
+
+// Close closes the database and the channels of the subscribers. The store
+// must not be used afterwards.
+func (s *SQLiteStore) Close() error {
+	s.notifier.close()
+	return errors.Join(s.readDB.Close(), s.db.Close())
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanPerson scans the personColumns of the row followed by the extra columns.
+func scanPerson(row rowScanner, extra ...any) (*Person, error) {
+	person := &Person{}
+	var birthDate, seeking sql.NullString
+	var heightMin, heightMax, ageMin, ageMax sql.NullInt64
+	var latitude, longitude, maxDistance sql.NullFloat64
+	dest := []any{&person.ID, &person.Name, &person.Height, &person.Gender, &person.NumberOfWantedDates, &birthDate,
+		&latitude, &longitude, &heightMin, &heightMax, &ageMin, &ageMax, &seeking, &maxDistance}
+	err := row.Scan(append(dest, extra...)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	person.BirthDate = birthDate.String
+	if latitude.Valid {
+		person.Location = &model.Location{Latitude: latitude.Float64, Longitude: longitude.Float64}
+	}
+	if heightMin.Valid || ageMin.Valid || seeking.Valid || maxDistance.Valid {
+		person.Preferences = &model.Preferences{}
+	}
+	if heightMin.Valid {
+		person.Preferences.Height = &model.HeightRange{Min: int(heightMin.Int64), Max: int(heightMax.Int64)}
+	}
+	if ageMin.Valid {
+		person.Preferences.Age = &model.AgeRange{Min: int(ageMin.Int64), Max: int(ageMax.Int64)}
+	}
+	if seeking.Valid {
+		if err := json.Unmarshal([]byte(seeking.String), &person.Preferences.Seeking); err != nil {
+			return nil, err
+		}
+	}
+	if maxDistance.Valid {
+		person.Preferences.MaxDistanceKm = &maxDistance.Float64
+	}
+	return person, nil
+}
+
+// personValues returns the values of personColumns.
+func personValues(id string, person *model.Person) ([]any, error) {
+	var birthDate, seeking sql.NullString
+	var heightMin, heightMax, ageMin, ageMax sql.NullInt64
+	var latitude, longitude, maxDistance sql.NullFloat64
+	if person.BirthDate != "" {
+		birthDate = sql.NullString{String: person.BirthDate, Valid: true}
+	}
+	if location := person.Location; location != nil {
+		latitude = sql.NullFloat64{Float64: location.Latitude, Valid: true}
+		longitude = sql.NullFloat64{Float64: location.Longitude, Valid: true}
+	}
+	if preferences := person.Preferences; preferences != nil {
+		if height := preferences.Height; height != nil {
+			heightMin = sql.NullInt64{Int64: int64(height.Min), Valid: true}
+			heightMax = sql.NullInt64{Int64: int64(height.Max), Valid: true}
+		}
+		if age := preferences.Age; age != nil {
+			ageMin = sql.NullInt64{Int64: int64(age.Min), Valid: true}
+			ageMax = sql.NullInt64{Int64: int64(age.Max), Valid: true}
+		}
+		if len(preferences.Seeking) > 0 {
+			bytes, err := json.Marshal(preferences.Seeking)
+			if err != nil {
+				return nil, err
+			}
+			seeking = sql.NullString{String: string(bytes), Valid: true}
+		}
+		if preferences.MaxDistanceKm != nil {
+			maxDistance = sql.NullFloat64{Float64: *preferences.MaxDistanceKm, Valid: true}
+		}
+	}
+	return []any{id, person.Name, person.Height, person.Gender, person.NumberOfWantedDates, birthDate,
+		latitude, longitude, heightMin, heightMax, ageMin, ageMax, seeking, maxDistance}, nil
+}
+
+func getPerson(q queryer, id string) (*Person, error) {
+	return scanPerson(q.QueryRow(`SELECT `+personColumns+` FROM people WHERE id = ?`, id))
+}
+
+// queryPeople returns at most limit people of the query kept by the filter,
+// a nil filter keeping everyone, and the last time they were active. The
+// query selects the personColumns followed by active_at.
+func queryPeople(q queryer, keep func(person *Person) bool, limit int, query string, args ...any) (People, map[string]time.Time, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	people := People{}
+	activeAt := map[string]time.Time{}
+	for len(people) < limit && rows.Next() {
+		var at sql.NullInt64
+		person, err := scanPerson(rows, &at)
+		if err != nil {
+			return nil, nil, err
+		}
+		if keep == nil || keep(person) {
+			people = append(people, person)
+			if at.Valid {
+				activeAt[person.ID] = time.Unix(0, at.Int64)
+			}
+		}
+	}
+	return people, activeAt, rows.Err()
+}
+
+// placeholders returns n comma separated bind parameters.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func (s *SQLiteStore) Add(id string, person *model.Person) (*Person, error) {
+	defer s.flush()
+	s.publishing.Lock()
+	defer s.publishing.Unlock()
+	values, err := personValues(id, person)
+	if err != nil {
+		return nil, err
+	}
+	at := s.now()
+	values = append(values, at.UnixNano())
+	_, err = s.db.Exec(`INSERT INTO people (`+personColumns+`, active_at) VALUES (`+placeholders(len(values))+`)`, values...)
+	if err != nil {
+		return nil, err
+	}
+	added := &Person{ID: id, Person: *person}
+	s.publish(PersonAdded{Person: *added, At: at.UTC()})
+	return added, nil
+}
+
+// Import inserts the people in a single transaction.
+func (s *SQLiteStore) Import(people People) error {
+	defer s.flush()
+	s.publishing.Lock()
+	defer s.publishing.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	at := s.now()
+	for _, person := range people {
+		values, err := personValues(person.ID, &person.Person)
+		if err != nil {
+			return err
+		}
+		values = append(values, at.UnixNano())
+		_, err = tx.Exec(`INSERT INTO people (`+personColumns+`, active_at) VALUES (`+placeholders(len(values))+`)`, values...)
+		if err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, person := range people {
+		s.publish(PersonAdded{Person: *person, At: at.UTC()})
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Get(id string) (*Person, error) {
+	return getPerson(s.db, id)
+}
+
+func (s *SQLiteStore) Remove(id string) error {
+	defer s.flush()
+	s.publishing.Lock()
+	defer s.publishing.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec(`DELETE FROM people WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	if err := forgetPerson(tx, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.publish(PersonRemoved{PersonID: id, At: s.now().UTC()})
+	return nil
+}
+
+func (s *SQLiteStore) Update(id string, update *model.PersonUpdate) (*Person, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	person, err := getPerson(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	update.Apply(&person.Person)
+	values, err := personValues(id, &person.Person)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`UPDATE people SET (`+personColumns+`) = (`+placeholders(len(values))+`) WHERE id = ?`,
+		append(values, id)...); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return person, nil
+}
+
+// forgetPerson deletes the swipes and the blocks of the person and on the person.
+func forgetPerson(q queryer, id string) error {
+	if _, err := q.Exec(`DELETE FROM swipes WHERE person = ? OR target = ?`, id, id); err != nil {
+		return err
+	}
+	_, err := q.Exec(`DELETE FROM blocks WHERE person = ? OR target = ?`, id, id)
+	return err
+}
+
+func (s *SQLiteStore) Match(id string) (*Person, error) {
+	defer s.flush()
+	s.publishing.Lock()
+	defer s.publishing.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	person, err := getPerson(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	possible, _, err := s.possibleMatchesSQL(tx, person, nil, candidateLimit(s.strategy))
+	if err != nil {
+		return nil, err
+	}
+	match := s.strategy.Pick(person, possible)
+	rec, err := s.match(tx, person, match)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.publish(matchedEvents(rec)...)
+	return match, nil
+}
+
+// match records the match of the two people and decreases their wanted
+// dates, deleting those who have no dates left.
+func (s *SQLiteStore) match(tx *sql.Tx, person *Person, match *Person) (*MatchRecord, error) {
+	rec := &MatchRecord{
+		ID:              s.idGenerator.GenerateKey(),
+		PersonA:         person.ID,
+		PersonB:         match.ID,
+		MatchedAt:       time.Unix(0, s.now().UnixNano()).UTC(),
+		RemainingDatesA: person.NumberOfWantedDates - 1,
+		RemainingDatesB: match.NumberOfWantedDates - 1,
+	}
+	matchedAt := rec.MatchedAt.UnixNano()
+	_, err := tx.Exec(`INSERT INTO matches (`+matchColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		rec.ID, rec.PersonA, rec.PersonB, matchedAt, rec.RemainingDatesA, rec.RemainingDatesB)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`DELETE FROM swipes WHERE (person = ? AND target = ?) OR (person = ? AND target = ?)`,
+		person.ID, match.ID, match.ID, person.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range []*Person{person, match} {
+		p.DecreaseDateCount()
+		if p.NumberOfWantedDates <= 0 {
+			if _, err = tx.Exec(`DELETE FROM people WHERE id = ?`, p.ID); err == nil {
+				err = forgetPerson(tx, p.ID)
+			}
+		} else {
+			_, err = tx.Exec(`UPDATE people SET number_of_wanted_dates = ?, active_at = ? WHERE id = ?`,
+				p.NumberOfWantedDates, matchedAt, p.ID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rec, nil
+}
+
+// swipeTargets looks up the person swiping and the target of the swipe,
+// who must be two people never matched to each other.
+func swipeTargets(q queryer, id string, targetID string) (*Person, *Person, error) {
+	if id == targetID {
+		return nil, nil, ErrSamePerson
+	}
+	person, err := getPerson(q, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := getPerson(q, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	matched, err := matchedWith(q, id, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if matched {
+		return nil, nil, ErrAlreadyMatched
+	}
+	return person, target, nil
+}
+
+// matchedWith reports whether the two people were already matched to each other.
+func matchedWith(q queryer, id string, targetID string) (bool, error) {
+	var matched bool
+	err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM matches
+		WHERE (person_a = ? AND person_b = ?) OR (person_a = ? AND person_b = ?))`, id, targetID, targetID, id).Scan(&matched)
+	return matched, err
+}
+
+func setSwipe(q queryer, id string, targetID string, liked bool) error {
+	_, err := q.Exec(`INSERT INTO swipes (person, target, liked) VALUES (?, ?, ?)
+		ON CONFLICT (person, target) DO UPDATE SET liked = excluded.liked`, id, targetID, liked)
+	return err
+}
+
+// List counts the people selected by the filter and reads the page after
+// the cursor in a single read transaction, the (height, id) index keeps the
+// rows in the order of the cursor.
+func (s *SQLiteStore) List(filter PeopleFilter, after *Cursor, limit int) (*PeoplePage, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	page := &PeoplePage{People: People{}}
+	args := peopleFilterArgs(filter)
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM people WHERE `+selectedBy, args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		return page, nil
+	}
+	cursor := &Cursor{Height: math.MinInt}
+	if after != nil {
+		cursor = after
+	}
+	// one more person than the page tells whether there is a next page.
+	people, _, err := queryPeople(tx, nil, limit+1, `SELECT `+personColumns+`, active_at FROM people
+		WHERE `+selectedBy+` AND (height, id) > (?, ?) ORDER BY height, id`,
+		append(args, cursor.Height, cursor.ID)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(people) > limit {
+		people = people[:limit]
+		page.Next = cursorOf(people[limit-1])
+	}
+	page.People = append(page.People, people...)
+	return page, nil
+}
+
+func (s *SQLiteStore) Like(id string, targetID string) (*MatchRecord, error) {
+	defer s.flush()
+	s.publishing.Lock()
+	defer s.publishing.Unlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	person, target, err := swipeTargets(tx, id, targetID)
+	if err != nil {
+		return nil, err
+	}
+	// the target liked the person and neither of them blocked the other one.
+	var liked bool
+	err = tx.QueryRow(`SELECT liked AND NOT EXISTS (SELECT 1 FROM blocks
+		WHERE (person = ? AND target = ?) OR (person = ? AND target = ?))
+		FROM swipes WHERE person = ? AND target = ?`, id, targetID, targetID, id, targetID, id).Scan(&liked)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	var rec *MatchRecord
+	if liked {
+		rec, err = s.match(tx, person, target)
+	} else {
+		err = setSwipe(tx, id, targetID, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if rec != nil {
+		s.publish(matchedEvents(rec)...)
+	}
+	return rec, nil
+}
+
+func (s *SQLiteStore) Pass(id string, targetID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, _, err := swipeTargets(tx, id, targetID); err != nil {
+		return err
+	}
+	if err := setSwipe(tx, id, targetID, false); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) PossibleMatches(id string, after *Cursor, maxNum int) (Candidates, *Cursor, error) {
+	if maxNum <= 0 {
+		return nil, nil, ErrNotFound
+	}
+	if err := after.checkOrder(s.rules.Order); err != nil {
+		return nil, nil, err
+	}
+	person, err := getPerson(s.db, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.possibleMatchesSQL(s.db, person, after, maxNum)
+}
+
+// possibleMatchesSQL is the range query counterpart of MemoryStore.possibleMatches,
+// every rule of the person is a range of the (gender, height, id) index. A
+// person bounding the distance also restricts the query to the bounding box
+// of the distance, the exact distances are checked on the rows read. The
+// rows after the cursor are compared as (height, id) row values.
+func (s *SQLiteStore) possibleMatchesSQL(q queryer, person *Person, after *Cursor, maxNum int) (Candidates, *Cursor, error) {
+	if person.NumberOfWantedDates == 0 {
+		return nil, nil, ErrNotFound
+	}
+	now := s.now()
+	var ranges []string
+	var args []any
+	for _, rule := range s.rules.searches(person) {
+		low, high := rule.searchRange(person)
+		ages := rule.ageRange(person, now)
+		if low > high || ages.low > ages.high {
+			continue
+		}
+		if !ages.constrained {
+			ranges = append(ranges, `(gender = ? AND height BETWEEN ? AND ?)`)
+			args = append(args, rule.CandidateGender, low, high)
+			continue
+		}
+		from, to := ages.birthDates(now)
+		ranges = append(ranges, `(gender = ? AND height BETWEEN ? AND ? AND birth_date IS NOT NULL
+			AND (? = '' OR birth_date >= ?) AND (? = '' OR birth_date <= ?))`)
+		args = append(args, rule.CandidateGender, low, high, from, from, to, to)
+	}
+	if len(ranges) == 0 {
+		return nil, nil, ErrNotFound
+	}
+	var age sql.NullInt64
+	if personAge, ok := person.Age(now); ok {
+		age = sql.NullInt64{Int64: int64(personAge), Valid: true}
+	}
+	order := `ORDER BY height, id`
+	if s.rules.Order == OrderDescending {
+		order = `ORDER BY height DESC, id DESC`
+	}
+	bounds := ``
+	if center, radiusKm, ok := maxDistance(person); ok {
+		box := boundingBoxOf(center, radiusKm)
+		bounds = ` AND latitude BETWEEN ? AND ? AND (longitude BETWEEN ? AND ?)`
+		if box.minLng > box.maxLng {
+			bounds = ` AND latitude BETWEEN ? AND ? AND (longitude >= ? OR longitude <= ?)`
+		}
+		args = append(args, box.minLat, box.maxLat, box.minLng, box.maxLng)
+	}
+	// the candidates ranked by score are only cut at the cursor once scored.
+	if after != nil && s.rules.Order != OrderScore {
+		if s.rules.Order == OrderDescending {
+			bounds += ` AND (height, id) < (?, ?)`
+		} else {
+			bounds += ` AND (height, id) > (?, ?)`
+		}
+		args = append(args, after.Height, after.ID)
+	}
+	seekers, err := json.Marshal(s.rules.seekers(person.Gender))
+	if err != nil {
+		return nil, nil, err
+	}
+	args = append(args, person.ID, person.ID, person.ID, person.ID, person.ID, person.ID, person.Height, age, string(seekers), person.Gender)
+	keep := func(candidate *Person) bool {
+		return s.rules.mutuallyAccepted(person, candidate, now)
+	}
+	matches, activeAt, err := queryPeople(q, keep, s.rules.limit(maxNum), `SELECT `+personColumns+`, active_at FROM people
+		WHERE (`+strings.Join(ranges, ` OR `)+`)`+bounds+` AND id != ? AND `+notMatchedWith+` AND `+notPassedBy+`
+		AND `+notBlocked+` AND `+acceptsPerson+`
+		`+order, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(matches) == 0 {
+		return nil, nil, ErrNotFound
+	}
+	candidates, next := s.rules.candidatesOf(person, matches, after, maxNum, func(id string) time.Time { return activeAt[id] }, now)
+	return candidates, next, nil
+}
+
+// blockTargets checks that the person and the target are two people in the pool.
+func blockTargets(q queryer, id string, targetID string) error {
+	if id == targetID {
+		return ErrSamePerson
+	}
+	if _, err := getPerson(q, id); err != nil {
+		return err
+	}
+	_, err := getPerson(q, targetID)
+	return err
+}
+
+// reportTargets checks that the person and the target are two people in the
+// pool, or two people matched to each other who may have been evicted since.
+func reportTargets(q queryer, id string, targetID string) error {
+	matched, err := matchedWith(q, id, targetID)
+	if err != nil {
+		return err
+	}
+	if matched {
+		return nil
+	}
+	return blockTargets(q, id, targetID)
+}
+
+func (s *SQLiteStore) Block(id string, targetID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := blockTargets(tx, id, targetID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT OR IGNORE INTO blocks (person, target) VALUES (?, ?)`, id, targetID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) Report(id string, targetID string, reason ReportReason, comment string) (*Report, error) {
+	if !reason.Valid() {
+		return nil, ErrUnknownReason
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if err := reportTargets(tx, id, targetID); err != nil {
+		return nil, err
+	}
+	report := &Report{
+		ID:         s.idGenerator.GenerateKey(),
+		Reporter:   id,
+		Target:     targetID,
+		Reason:     reason,
+		Comment:    comment,
+		ReportedAt: time.Unix(0, s.now().UnixNano()).UTC(),
+	}
+	_, err = tx.Exec(`INSERT INTO reports (`+reportColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		report.ID, report.Reporter, report.Target, report.Reason, report.Comment, report.ReportedAt.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *SQLiteStore) Reports(filter ReportFilter) ([]Report, error) {
+	rows, err := s.db.Query(`SELECT `+reportColumns+` FROM reports
+		WHERE (? = '' OR reason = ?) AND (? = '' OR target = ?) ORDER BY rowid`,
+		filter.Reason, filter.Reason, filter.Target, filter.Target)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	reports := []Report{}
+	for rows.Next() {
+		report := Report{}
+		var reportedAt int64
+		err := rows.Scan(&report.ID, &report.Reporter, &report.Target, &report.Reason, &report.Comment, &reportedAt)
+		if err != nil {
+			return nil, err
+		}
+		report.ReportedAt = time.Unix(0, reportedAt).UTC()
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+func scanMatch(row rowScanner) (*MatchRecord, error) {
+	rec := &MatchRecord{}
+	var matchedAt int64
+	err := row.Scan(&rec.ID, &rec.PersonA, &rec.PersonB, &matchedAt, &rec.RemainingDatesA, &rec.RemainingDatesB)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	rec.MatchedAt = time.Unix(0, matchedAt).UTC()
+	return rec, nil
+}
+
+func (s *SQLiteStore) History(id string) ([]MatchRecord, error) {
+	history, err := queryHistory(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		if _, err := getPerson(s.db, id); err != nil {
+			return nil, err
+		}
+	}
+	return history, nil
+}
+
+func (s *SQLiteStore) GetMatch(matchID string) (*MatchRecord, error) {
+	return scanMatch(s.db.QueryRow(`SELECT `+matchColumns+` FROM matches WHERE id = ?`, matchID))
+}
+
+// queryHistory returns the matches of the person, oldest first.
+func queryHistory(q queryer, id string) ([]MatchRecord, error) {
+	rows, err := q.Query(`SELECT `+matchColumns+` FROM matches
+		WHERE person_a = ? OR person_b = ? ORDER BY rowid`, id, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	history := []MatchRecord{}
+	for rows.Next() {
+		rec, err := scanMatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, *rec)
+	}
+	return history, rows.Err()
+}
+
+// Export reads the people row by row on the (height, id) index in a read
+// transaction, which is the point-in-time view of the pool, and looks up the
+// history of every person in the same transaction. The transaction is on a
+// connection of its own and, the database being in WAL mode, neither waits
+// for the writers nor holds them up.
+func (s *SQLiteStore) Export(visit func(person *Person, history []MatchRecord) error) error {
+	tx, err := s.readDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	rows, err := tx.Query(`SELECT ` + personColumns + ` FROM people ORDER BY height, id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		person, err := scanPerson(rows)
+		if err != nil {
+			return err
+		}
+		history, err := queryHistory(tx, person.ID)
+		if err != nil {
+			return err
+		}
+		if err := visit(person, history); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (s *SQLiteStore) Subscribe(personID string) (<-chan Event, func()) {
+	return s.notifier.Subscribe(personID)
+}
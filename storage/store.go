@@ -0,0 +1,58 @@
+package storage
+
+import "github.com/bito_interview/model"
+
+// Store is the candidate pool of the matching system.
+type Store interface {
+	// Add stores the person under the given id.
+	Add(id string, person *model.Person) (*Person, error)
+	// Import adds the people under their ids in a single batch, none of them
+	// is added when the batch fails.
+	Import(people People) error
+	// Export visits everyone in the pool in the order of height then id along
+	// with his/her match history, all from a point-in-time view of the pool.
+	// Visiting stops at the first error returned by visit, which Export returns.
+	Export(visit func(person *Person, history []MatchRecord) error) error
+	// Get returns the person with the given id.
+	Get(id string) (*Person, error)
+	// List returns at most limit people selected by the filter after the
+	// cursor, along with the number of people selected in every page.
+	List(filter PeopleFilter, after *Cursor, limit int) (*PeoplePage, error)
+	// Remove deletes the person from the pool.
+	Remove(id string) error
+	// Update applies the partial update to the person, who keeps his/her id,
+	// matches, swipes and blocks.
+	Update(id string, update *model.PersonUpdate) (*Person, error)
+	// Match pairs the person with the possible match picked by the match
+	// strategy and decreases the wanted dates of both sides, evicting anyone
+	// who has no dates left.
+	Match(id string) (*Person, error)
+	// PossibleMatches returns at most maxNum possible matches of the person
+	// after the cursor and their distances from the person, along with the
+	// cursor of the next page, nil on the last page. A nil cursor starts from
+	// the first possible match.
+	PossibleMatches(id string, after *Cursor, maxNum int) (Candidates, *Cursor, error)
+	// Like records that the person likes the target. Once both people liked
+	// each other they are matched like Match does and the match is returned,
+	// otherwise the returned match is nil. People blocked by either of them
+	// are never matched.
+	Like(id string, targetID string) (*MatchRecord, error)
+	// Pass records that the person passes on the target, who is no longer a
+	// possible match of the person.
+	Pass(id string, targetID string) error
+	// Block records that the person blocks the target, neither of them is a
+	// possible match of the other one afterwards.
+	Block(id string, targetID string) error
+	// Report records a report of the target by the person.
+	Report(id string, targetID string, reason ReportReason, comment string) (*Report, error)
+	// Reports returns the reports selected by the filter, oldest first.
+	Reports(filter ReportFilter) ([]Report, error)
+	// History returns the matches of the person, oldest first, including
+	// those made before the person was removed from the pool.
+	History(id string) ([]MatchRecord, error)
+	// GetMatch returns the match with the given id.
+	GetMatch(matchID string) (*MatchRecord, error)
+	// Subscribe returns the channel of the match, removal and eviction events
+	// of the person and the function unsubscribing from them.
+	Subscribe(personID string) (<-chan Event, func())
+}
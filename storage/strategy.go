@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// MatchStrategy picks the partner of a person among his/her possible matches.
+type MatchStrategy interface {
+	// Pick returns one of the possible matches, which are in the order of the
+	// rules and never empty.
+	Pick(person *Person, candidates Candidates) *Person
+}
+
+// FirstMatch picks the first possible match in the order of the rules.
+type FirstMatch struct{}
+
+func (FirstMatch) Pick(person *Person, candidates Candidates) *Person {
+	return candidates[0].Person
+}
+
+// ClosestHeight picks the possible match whose height is the closest to the
+// height of the person, the first one in the order of the rules among equals.
+type ClosestHeight struct{}
+
+func (ClosestHeight) Pick(person *Person, candidates Candidates) *Person {
+	picked := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if abs(candidate.Height-person.Height) < abs(picked.Height-person.Height) {
+			picked = candidate
+		}
+	}
+	return picked.Person
+}
+
+// HighestScore picks the possible match with the highest score, the first
+// one in the order of the rules among equals.
+type HighestScore struct{}
+
+func (HighestScore) Pick(person *Person, candidates Candidates) *Person {
+	picked := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate.Score > picked.Score {
+			picked = candidate
+		}
+	}
+	return picked.Person
+}
+
+// RoundRobin picks the possible match who has been waiting the longest since
+// being added or last matched, so that everyone gets matched in turn.
+type RoundRobin struct{}
+
+func (RoundRobin) Pick(person *Person, candidates Candidates) *Person {
+	picked := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate.ActiveAt.Before(picked.ActiveAt) {
+			picked = candidate
+		}
+	}
+	return picked.Person
+}
+
+// RandomMatch picks any possible match with the same probability.
+type RandomMatch struct {
+	mutex *sync.Mutex
+	rng   *rand.Rand
+}
+
+// NewRandomMatch returns a RandomMatch drawing from rng, which is seeded to
+// make the picks reproducible.
+func NewRandomMatch(rng *rand.Rand) *RandomMatch {
+	return &RandomMatch{mutex: &sync.Mutex{}, rng: rng}
+}
+
+func (r *RandomMatch) Pick(person *Person, candidates Candidates) *Person {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return candidates[r.rng.Intn(len(candidates))].Person
+}
+
+// ParseMatchStrategy returns the strategy of the given name: first,
+// closest-height, highest-score, round-robin or random, the random strategy
+// is seeded with seed.
+func ParseMatchStrategy(name string, seed int64) (MatchStrategy, error) {
+	switch name {
+	case "first":
+		return FirstMatch{}, nil
+	case "closest-height":
+		return ClosestHeight{}, nil
+	case "highest-score":
+		return HighestScore{}, nil
+	case "round-robin":
+		return RoundRobin{}, nil
+	case "random":
+		return NewRandomMatch(rand.New(rand.NewSource(seed))), nil
+	}
+	return nil, fmt.Errorf("unknown match strategy %q", name)
+}
+
+// candidateLimit is the number of possible matches the strategy picks from.
+func candidateLimit(strategy MatchStrategy) int {
+	if _, ok := strategy.(FirstMatch); ok {
+		return 1
+	}
+	return math.MaxInt
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
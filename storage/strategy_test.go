@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/bito_interview/model"
+)
+
+func TestMatchStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy func() MatchStrategy
+		match    string
+	}{
+		{
+			name:     "first",
+			strategy: func() MatchStrategy { return FirstMatch{} },
+			match:    "3",
+		},
+		{
+			name:     "closest_height",
+			strategy: func() MatchStrategy { return ClosestHeight{} },
+			match:    "2",
+		},
+		{
+			name:     "highest_score",
+			strategy: func() MatchStrategy { return HighestScore{} },
+			match:    "5",
+		},
+		{
+			name:     "round_robin",
+			strategy: func() MatchStrategy { return RoundRobin{} },
+			match:    "4",
+		},
+		{
+			name:     "random",
+			strategy: func() MatchStrategy { return NewRandomMatch(rand.New(rand.NewSource(1))) },
+			match:    "4",
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				rules := DefaultRules()
+				rules.Order = OrderDescending
+				rules.Weights = &ScoreWeights{Distance: 1}
+				now := testTime
+				s := backend.new(t, WithRules(rules), WithMatchStrategy(test.strategy()), WithClock(func() time.Time { return now }))
+				// everyone is added a day after the previous one.
+				people := People{
+					withLocation(createPerson("1", model.GenderFemale, 10, 3), taipei),
+					createPerson("4", model.GenderMale, 15, 1),
+					createPerson("3", model.GenderMale, 18, 1),
+					createPerson("2", model.GenderMale, 12, 1),
+					withLocation(createPerson("5", model.GenderMale, 13, 1), taoyuan),
+				}
+				for _, person := range people {
+					if _, err := s.Add(person.ID, &person.Person); err != nil {
+						t.Fatal(err)
+					}
+					now = now.Add(24 * time.Hour)
+				}
+				match, err := s.Match("1")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := match.ID, test.match; got != want {
+					t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestRandomMatchReproducible(t *testing.T) {
+	candidates := Candidates{
+		{Person: createPerson("1", model.GenderMale, 11, 1)},
+		{Person: createPerson("2", model.GenderMale, 12, 1)},
+		{Person: createPerson("3", model.GenderMale, 13, 1)},
+		{Person: createPerson("4", model.GenderMale, 14, 1)},
+	}
+	person := createPerson("0", model.GenderFemale, 10, 1)
+	first, second := NewRandomMatch(rand.New(rand.NewSource(42))), NewRandomMatch(rand.New(rand.NewSource(42)))
+	picked := map[string]bool{}
+	for range 100 {
+		got, want := first.Pick(person, candidates), second.Pick(person, candidates)
+		if got != want {
+			t.Fatalf("%s got %v but want: %v", t.Name(), got.ID, want.ID)
+		}
+		picked[got.ID] = true
+	}
+	if got, want := len(picked), len(candidates); got != want {
+		t.Errorf("%s got %v candidates picked but want: %v", t.Name(), got, want)
+	}
+}
@@ -0,0 +1,134 @@
+package storage
+
+import "errors"
+
+var (
+	ErrSamePerson     = errors.New("cannot swipe on oneself")
+	ErrAlreadyMatched = errors.New("already matched")
+)
+
+// swipes keep whether every person liked or passed on other people, along
+// with who swiped on every person so that a removed person is forgotten on
+// both sides.
+type swipes struct {
+	// liked[id][target] is true when id liked target and false when id passed on target.
+	liked    map[string]map[string]bool
+	swipedBy map[string]map[string]struct{}
+}
+
+func newSwipes() *swipes {
+	return &swipes{liked: map[string]map[string]bool{}, swipedBy: map[string]map[string]struct{}{}}
+}
+
+func (s *swipes) set(id string, target string, liked bool) {
+	if s.liked[id] == nil {
+		s.liked[id] = map[string]bool{}
+	}
+	s.liked[id][target] = liked
+	if s.swipedBy[target] == nil {
+		s.swipedBy[target] = map[string]struct{}{}
+	}
+	s.swipedBy[target][id] = struct{}{}
+}
+
+// likes reports whether the person liked the target.
+func (s *swipes) likes(id string, target string) bool {
+	return s.liked[id][target]
+}
+
+// passed reports whether the person passed on the target.
+func (s *swipes) passed(id string, target string) bool {
+	liked, ok := s.liked[id][target]
+	return ok && !liked
+}
+
+// forgetPair forgets the swipes of the two people on each other.
+func (s *swipes) forgetPair(id string, other string) {
+	s.unset(id, other)
+	s.unset(other, id)
+}
+
+func (s *swipes) unset(id string, target string) {
+	delete(s.liked[id], target)
+	if len(s.liked[id]) == 0 {
+		delete(s.liked, id)
+	}
+	delete(s.swipedBy[target], id)
+	if len(s.swipedBy[target]) == 0 {
+		delete(s.swipedBy, target)
+	}
+}
+
+// forget forgets the swipes of the person and on the person.
+func (s *swipes) forget(id string) {
+	for target := range s.liked[id] {
+		s.unset(id, target)
+	}
+	for swiper := range s.swipedBy[id] {
+		s.unset(swiper, id)
+	}
+}
+
+// swipeTargets looks up the person swiping and the target of the swipe,
+// who must be two people never matched to each other.
+func (s *MemoryStore) swipeTargets(id string, targetID string) (*Person, *Person, error) {
+	if id == targetID {
+		return nil, nil, ErrSamePerson
+	}
+	person, err := s.all.getPerson(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := s.all.getPerson(targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.ledger.matched(id, targetID) {
+		return nil, nil, ErrAlreadyMatched
+	}
+	return person, target, nil
+}
+
+func (s *MemoryStore) Like(id string, targetID string) (*MatchRecord, error) {
+	defer s.flush()
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	person, target, err := s.swipeTargets(id, targetID)
+	if err != nil {
+		return nil, err
+	}
+	var rec *MatchRecord
+	if s.likedBack(id, targetID) {
+		rec = s.newMatchRecord(person, target)
+	}
+	return s.like(person, target, rec), nil
+}
+
+// likedBack reports whether the target liked the person and neither of them
+// blocked the other one, so that liking the target matches them.
+func (s *MemoryStore) likedBack(id string, targetID string) bool {
+	return s.swipes.likes(targetID, id) && !s.blocks.between(id, targetID)
+}
+
+// like records that the person likes the target, or matches them with the
+// given record when the target already liked the person. The returned
+// record is a copy of the match, nil when there is none.
+func (s *MemoryStore) like(person *Person, target *Person, rec *MatchRecord) *MatchRecord {
+	if rec == nil {
+		s.swipes.set(person.ID, target.ID, true)
+		return nil
+	}
+	s.match(person, target, rec)
+	copied := *rec
+	return &copied
+}
+
+func (s *MemoryStore) Pass(id string, targetID string) error {
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+	if _, _, err := s.swipeTargets(id, targetID); err != nil {
+		return err
+	}
+	s.swipes.set(id, targetID, false)
+	return nil
+}
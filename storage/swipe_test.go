@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/bito_interview/model"
+	"github.com/google/go-cmp/cmp"
+)
+
+type swipe struct {
+	id      string
+	target  string
+	pass    bool
+	matched bool
+	err     error
+}
+
+func TestLikeAndPass(t *testing.T) {
+	tests := []struct {
+		name     string
+		swipes   []swipe
+		possible []string
+		history  int
+	}{
+		{
+			name:     "one_sided_like",
+			swipes:   []swipe{{id: "1", target: "2"}},
+			possible: []string{"2", "3"},
+		},
+		{
+			name:     "mutual_like",
+			swipes:   []swipe{{id: "1", target: "2"}, {id: "2", target: "1", matched: true}},
+			possible: []string{"3"},
+			history:  1,
+		},
+		{
+			name:     "like_twice",
+			swipes:   []swipe{{id: "1", target: "2"}, {id: "1", target: "2"}},
+			possible: []string{"2", "3"},
+		},
+		{
+			name:     "pass",
+			swipes:   []swipe{{id: "1", target: "2", pass: true}, {id: "2", target: "1"}},
+			possible: []string{"3"},
+		},
+		{
+			name:     "like_after_pass",
+			swipes:   []swipe{{id: "1", target: "2", pass: true}, {id: "1", target: "2"}, {id: "2", target: "1", matched: true}},
+			possible: []string{"3"},
+			history:  1,
+		},
+		{
+			name:     "pass_after_like",
+			swipes:   []swipe{{id: "1", target: "2"}, {id: "1", target: "2", pass: true}, {id: "2", target: "1"}},
+			possible: []string{"3"},
+		},
+		{
+			name:     "same_person",
+			swipes:   []swipe{{id: "1", target: "1", err: ErrSamePerson}, {id: "1", target: "1", pass: true, err: ErrSamePerson}},
+			possible: []string{"2", "3"},
+		},
+		{
+			name:     "unknown_target",
+			swipes:   []swipe{{id: "1", target: "5", err: ErrNotFound}, {id: "5", target: "1", pass: true, err: ErrNotFound}},
+			possible: []string{"2", "3"},
+		},
+		{
+			name: "already_matched",
+			swipes: []swipe{
+				{id: "1", target: "3"},
+				{id: "3", target: "1", matched: true},
+				{id: "1", target: "3", err: ErrAlreadyMatched},
+				{id: "3", target: "1", pass: true, err: ErrAlreadyMatched},
+			},
+			possible: []string{"2"},
+			history:  1,
+		},
+		{
+			name:     "evicted",
+			swipes:   []swipe{{id: "4", target: "2"}, {id: "2", target: "4", matched: true}, {id: "1", target: "2", err: ErrNotFound}},
+			possible: []string{"3"},
+		},
+	}
+
+	for _, backend := range backends {
+		for _, test := range tests {
+			t.Run(backend.name+"/"+test.name, func(t *testing.T) {
+				t.Parallel()
+				s := setupTest(t, backend,
+					createPerson("1", model.GenderFemale, 10, 2),
+					createPerson("2", model.GenderMale, 12, 1),
+					createPerson("3", model.GenderMale, 14, 2),
+					createPerson("4", model.GenderFemale, 8, 2),
+				)
+				for _, swipe := range test.swipes {
+					var match *MatchRecord
+					var err error
+					if swipe.pass {
+						err = s.Pass(swipe.id, swipe.target)
+					} else {
+						match, err = s.Like(swipe.id, swipe.target)
+					}
+					if got, want := err, swipe.err; got != want {
+						t.Errorf("%s got %v but want: %v", t.Name(), got, want)
+					}
+					if got, want := match != nil, swipe.matched; got != want {
+						t.Errorf("%s got matched %v but want: %v", t.Name(), got, want)
+					}
+					if match != nil && (match.PersonA != swipe.id || match.PersonB != swipe.target) {
+						t.Errorf("%s got match of %v and %v but want: %v and %v", t.Name(), match.PersonA, match.PersonB, swipe.id, swipe.target)
+					}
+				}
+				got, _, _ := s.PossibleMatches("1", nil, 5)
+				var ids []string
+				for _, match := range got {
+					ids = append(ids, match.ID)
+				}
+				if diff := cmp.Diff(ids, test.possible); diff != "" {
+					t.Errorf("%s got want:\n%s", t.Name(), diff)
+				}
+				history, err := s.History("1")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, want := len(history), test.history; got != want {
+					t.Errorf("%s got %v matches but want: %v", t.Name(), got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestDurableSwipesRecovery(t *testing.T) {
+	for _, snapshotEvery := range []int{2, 100} {
+		dir := t.TempDir()
+		s := openDurable(t, dir, snapshotEvery)
+		for _, person := range (People{
+			createPerson("1", model.GenderFemale, 10, 2),
+			createPerson("2", model.GenderMale, 12, 2),
+			createPerson("3", model.GenderMale, 14, 2),
+		}) {
+			if _, err := s.Add(person.ID, &person.Person); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := s.Like("2", "1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Pass("1", "3"); err != nil {
+			t.Fatal(err)
+		}
+		s.Close()
+
+		s = openDurable(t, dir, snapshotEvery)
+		match, err := s.Like("1", "2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if match == nil {
+			t.Errorf("%s got no match after recovery with snapshots every %v", t.Name(), snapshotEvery)
+		}
+		if _, _, err := s.PossibleMatches("1", nil, 5); err != ErrNotFound {
+			t.Errorf("%s got %v but want: %v", t.Name(), err, ErrNotFound)
+		}
+		s.Close()
+	}
+}
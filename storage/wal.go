@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bito_interview/model"
+)
+
+type walOp string
+
+const (
+	walOpAdd    walOp = "add"
+	walOpImport walOp = "import"
+	walOpRemove walOp = "remove"
+	walOpUpdate walOp = "update"
+	walOpMatch  walOp = "match"
+	walOpLike   walOp = "like"
+	walOpPass   walOp = "pass"
+	walOpBlock  walOp = "block"
+	walOpReport walOp = "report"
+)
+
+// walRecord is a single mutation of the candidate pool, the person of an
+// update is the person once updated and an import carries all the people
+// imported. At is the time people are added and Target is the person liked,
+// passed on or blocked. A like matching both people carries the match.
+type walRecord struct {
+	LSN    uint64        `json:"lsn"`
+	Op     walOp         `json:"op"`
+	ID     string        `json:"id,omitempty"`
+	Person *model.Person `json:"person,omitempty"`
+	People People        `json:"people,omitempty"`
+	At     time.Time     `json:"at"`
+	Target string        `json:"target,omitempty"`
+	Match  *MatchRecord  `json:"match,omitempty"`
+	Report *Report       `json:"report,omitempty"`
+}
+
+// walHeaderSize is the size of the length and checksum prefixing every record.
+const walHeaderSize = 8
+
+// maxWALRecordSize bounds the payload of a record, so that a corrupt length
+// does not allocate up to 4GiB when replaying.
+const maxWALRecordSize = 64 << 20
+
+var (
+	errTornRecord     = errors.New("torn wal record")
+	errRecordTooLarge = errors.New("wal record too large")
+)
+
+// wal is an append-only log of pool mutations. Each record is framed as
+// a 4-byte payload length, a 4-byte CRC32 of the payload and the JSON payload.
+type wal struct {
+	file *os.File
+	lsn  uint64
+	size int64
+}
+
+// openWAL replays every record after the given LSN and opens the log for
+// appending. A torn or corrupted tail left by a crash is truncated.
+func openWAL(path string, after uint64, apply func(rec *walRecord) error) (*wal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	w := &wal{file: file, lsn: after}
+	valid, err := w.replay(after, apply)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Truncate(valid); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(valid, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+	w.size = valid
+	return w, nil
+}
+
+// replay applies the records and returns the offset right after the last valid record.
+func (w *wal) replay(after uint64, apply func(rec *walRecord) error) (int64, error) {
+	reader := bufio.NewReader(w.file)
+	var offset int64
+	for {
+		rec, size, err := readRecord(reader)
+		if err == io.EOF || err == errTornRecord {
+			return offset, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if rec.LSN > after {
+			if err := apply(rec); err != nil {
+				return 0, fmt.Errorf("replay wal record %d: %w", rec.LSN, err)
+			}
+			w.lsn = rec.LSN
+		}
+		offset += size
+	}
+}
+
+func readRecord(reader io.Reader) (*walRecord, int64, error) {
+	header := make([]byte, walHeaderSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		if err == io.EOF {
+			return nil, 0, io.EOF
+		}
+		return nil, 0, errTornRecord
+	}
+	length := binary.BigEndian.Uint32(header[:4])
+	if length > maxWALRecordSize {
+		return nil, 0, errTornRecord
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, 0, errTornRecord
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, 0, errTornRecord
+	}
+	rec := &walRecord{}
+	if err := json.Unmarshal(payload, rec); err != nil {
+		return nil, 0, errTornRecord
+	}
+	return rec, walHeaderSize + int64(length), nil
+}
+
+// append assigns the next LSN to the record and writes it durably to the log.
+func (w *wal) append(rec walRecord) error {
+	rec.LSN = w.lsn + 1
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxWALRecordSize {
+		return errRecordTooLarge
+	}
+	buf := make([]byte, walHeaderSize, walHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:], crc32.ChecksumIEEE(payload))
+	buf = append(buf, payload...)
+	if _, err := w.file.Write(buf); err != nil {
+		w.rollback()
+		return err
+	}
+	if err := w.file.Sync(); err != nil {
+		w.rollback()
+		return err
+	}
+	w.lsn = rec.LSN
+	w.size += int64(len(buf))
+	return nil
+}
+
+// rollback drops a partially written record so later records are not appended after garbage.
+func (w *wal) rollback() {
+	w.file.Truncate(w.size)
+	w.file.Seek(w.size, io.SeekStart)
+}
+
+// reset empties the log once its records are covered by a snapshot.
+func (w *wal) reset() error {
+	if err := w.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	w.size = 0
+	return nil
+}
+
+func (w *wal) close() error {
+	return w.file.Close()
+}
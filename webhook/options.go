@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bito_interview/storage"
+)
+
+const (
+	// DefaultMaxAttempts is the number of attempts of a delivery before it
+	// is moved to the dead-letter list.
+	DefaultMaxAttempts = 5
+	// DefaultBackoff is the wait before the first retry, doubled before
+	// every other retry.
+	DefaultBackoff = time.Second
+	// MaxBackoff is the longest wait between two retries.
+	MaxBackoff = time.Minute
+	// DefaultMaxLogs is the number of deliveries kept per subscription and
+	// of deliveries kept in the dead-letter list.
+	DefaultMaxLogs = 100
+)
+
+type options struct {
+	client      *http.Client
+	idGenerator storage.IDGenerator
+	now         func() time.Time
+	maxAttempts int
+	backoff     time.Duration
+	maxLogs     int
+}
+
+// Option configures a Dispatcher.
+type Option func(*options)
+
+// WithClient sets the client sending the deliveries, a client timing out
+// after 10 seconds by default.
+func WithClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithIDGenerator sets the generator of subscription and delivery ids, UUIDGenerator by default.
+func WithIDGenerator(idGenerator storage.IDGenerator) Option {
+	return func(o *options) {
+		o.idGenerator = idGenerator
+	}
+}
+
+// WithClock sets the clock timestamping subscriptions and attempts, time.Now by default.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithRetries sets the number of attempts of a delivery and the wait before
+// the first retry, DefaultMaxAttempts and DefaultBackoff by default. A number
+// of attempts below 1 or a backoff not positive keeps its default, and the
+// backoff is capped at MaxBackoff.
+func WithRetries(maxAttempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.maxAttempts = maxAttempts
+		o.backoff = backoff
+	}
+}
+
+func newOptions(opts ...Option) options {
+	o := options{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		idGenerator: storage.UUIDGenerator{},
+		now:         time.Now,
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultBackoff,
+		maxLogs:     DefaultMaxLogs,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxAttempts < 1 {
+		o.maxAttempts = DefaultMaxAttempts
+	}
+	if o.backoff <= 0 {
+		o.backoff = DefaultBackoff
+	}
+	o.backoff = min(o.backoff, MaxBackoff)
+	return o
+}
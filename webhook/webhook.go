@@ -0,0 +1,296 @@
+// Package webhook delivers the events of the candidate pool to the URLs
+// subscribed to them, signing every payload with the secret of the
+// subscription and retrying failed deliveries with exponential backoff.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bito_interview/storage"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// Headers of every delivery.
+const (
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Subscription delivers the events of the given types to the URL.
+type Subscription struct {
+	ID     string              `json:"id"`
+	URL    string              `json:"url"`
+	Events []storage.EventType `json:"events"`
+	// Secret signs the payloads, it is never returned once subscribed.
+	Secret    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *Subscription) wants(eventType storage.EventType) bool {
+	return slices.Contains(s.Events, eventType)
+}
+
+// DeliveryStatus is where a delivery stands.
+type DeliveryStatus string
+
+const (
+	StatusPending   DeliveryStatus = "pending"
+	StatusDelivered DeliveryStatus = "delivered"
+	// StatusDead is a delivery given up after every attempt failed, which is
+	// kept in the dead-letter list.
+	StatusDead DeliveryStatus = "dead"
+)
+
+// Attempt is a request of a delivery, StatusCode is 0 when no response was
+// received.
+type Attempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Delivery is the event sent to a subscription along with every attempt of
+// sending it.
+type Delivery struct {
+	ID             string         `json:"id"`
+	SubscriptionID string         `json:"subscription_id"`
+	Event          storage.Event  `json:"event"`
+	Status         DeliveryStatus `json:"status"`
+	Attempts       []Attempt      `json:"attempts"`
+}
+
+func (d *Delivery) copy() Delivery {
+	copied := *d
+	copied.Attempts = slices.Clone(d.Attempts)
+	return copied
+}
+
+// Sign returns the signature of the payload sent at the unix timestamp, the
+// hex encoded HMAC-SHA256 of the timestamp, a dot and the payload prefixed
+// with "sha256=".
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher keeps the subscriptions and delivers the events published to
+// them. Every delivery is sent from its own goroutine, so deliveries are not
+// ordered. The subscriptions and the logs are kept in memory.
+type Dispatcher struct {
+	subscriptions map[string]*Subscription
+	// deliveries are the latest deliveries of every subscription, oldest first.
+	deliveries map[string][]*Delivery
+	// deadLetters are the latest deliveries given up, oldest first.
+	deadLetters []*Delivery
+	mutex       sync.Mutex
+	// closed drops the events published after Close.
+	closed  bool
+	sending sync.WaitGroup
+	options
+}
+
+func NewDispatcher(opts ...Option) *Dispatcher {
+	return &Dispatcher{
+		subscriptions: map[string]*Subscription{},
+		deliveries:    map[string][]*Delivery{},
+		options:       newOptions(opts...),
+	}
+}
+
+// Subscribe registers the URL for the events of the given types.
+func (d *Dispatcher) Subscribe(url string, events []storage.EventType, secret string) *Subscription {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	subscription := &Subscription{
+		ID:        d.idGenerator.GenerateKey(),
+		URL:       url,
+		Events:    slices.Clone(events),
+		Secret:    secret,
+		CreatedAt: d.now().UTC(),
+	}
+	d.subscriptions[subscription.ID] = subscription
+	copied := *subscription
+	return &copied
+}
+
+// Unsubscribe deletes the subscription and its delivery logs, its pending
+// deliveries are still attempted.
+func (d *Dispatcher) Unsubscribe(id string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if _, ok := d.subscriptions[id]; !ok {
+		return ErrNotFound
+	}
+	delete(d.subscriptions, id)
+	delete(d.deliveries, id)
+	return nil
+}
+
+// Subscriptions returns every subscription, oldest first.
+func (d *Dispatcher) Subscriptions() []Subscription {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	subscriptions := make([]Subscription, 0, len(d.subscriptions))
+	for _, subscription := range d.subscriptions {
+		subscriptions = append(subscriptions, *subscription)
+	}
+	slices.SortFunc(subscriptions, func(a, b Subscription) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return subscriptions
+}
+
+// Deliveries returns the latest deliveries of the subscription, oldest first.
+func (d *Dispatcher) Deliveries(subscriptionID string) ([]Delivery, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if _, ok := d.subscriptions[subscriptionID]; !ok {
+		return nil, ErrNotFound
+	}
+	return copyDeliveries(d.deliveries[subscriptionID]), nil
+}
+
+// DeadLetters returns the deliveries given up, oldest first.
+func (d *Dispatcher) DeadLetters() []Delivery {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return copyDeliveries(d.deadLetters)
+}
+
+func copyDeliveries(deliveries []*Delivery) []Delivery {
+	copied := make([]Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		copied = append(copied, delivery.copy())
+	}
+	return copied
+}
+
+// Publish starts delivering the event to the subscriptions of its type
+// without waiting for the deliveries.
+func (d *Dispatcher) Publish(event storage.Event) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.closed {
+		return
+	}
+	for _, subscription := range d.subscriptions {
+		if !subscription.wants(event.Type) {
+			continue
+		}
+		delivery := &Delivery{
+			ID:             d.idGenerator.GenerateKey(),
+			SubscriptionID: subscription.ID,
+			Event:          event,
+			Status:         StatusPending,
+		}
+		logs := append(d.deliveries[subscription.ID], delivery)
+		if len(logs) > d.maxLogs {
+			logs = slices.Delete(logs, 0, len(logs)-d.maxLogs)
+		}
+		d.deliveries[subscription.ID] = logs
+		d.sending.Add(1)
+		go d.deliver(*subscription, delivery)
+	}
+}
+
+// Handle publishes the events of the people affected by the domain event,
+// so that the dispatcher subscribes to the event bus of the store.
+func (d *Dispatcher) Handle(event storage.DomainEvent) {
+	for _, notification := range storage.NotificationsOf(event) {
+		d.Publish(notification)
+	}
+}
+
+// deliver sends the delivery until it is accepted or every attempt failed,
+// waiting twice as long before every retry, up to MaxBackoff.
+func (d *Dispatcher) deliver(subscription Subscription, delivery *Delivery) {
+	defer d.sending.Done()
+	payload, err := json.Marshal(delivery.Event)
+	if err != nil {
+		d.attempted(delivery, Attempt{At: d.now().UTC(), Error: err.Error()}, true)
+		return
+	}
+	backoff := d.backoff
+	for attempt := 1; ; attempt++ {
+		result := d.send(subscription, delivery, payload)
+		last := attempt == d.maxAttempts
+		if d.attempted(delivery, result, last) || last {
+			return
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, MaxBackoff)
+	}
+}
+
+func (d *Dispatcher) send(subscription Subscription, delivery *Delivery, payload []byte) Attempt {
+	now := d.now()
+	attempt := Attempt{At: now.UTC()}
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderEvent, string(delivery.Event.Type))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, now.Unix(), payload))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("unexpected status %s", resp.Status)
+	}
+	return attempt
+}
+
+// attempted logs the attempt and reports whether the delivery succeeded. A
+// delivery whose last attempt failed is moved to the dead-letter list.
+func (d *Dispatcher) attempted(delivery *Delivery, attempt Attempt, last bool) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	switch {
+	case attempt.Error == "":
+		delivery.Status = StatusDelivered
+		return true
+	case last:
+		delivery.Status = StatusDead
+		d.deadLetters = append(d.deadLetters, delivery)
+		if len(d.deadLetters) > d.maxLogs {
+			d.deadLetters = slices.Delete(d.deadLetters, 0, len(d.deadLetters)-d.maxLogs)
+		}
+	}
+	return false
+}
+
+// Close waits until every pending delivery is delivered or given up, the
+// events published afterwards are dropped.
+func (d *Dispatcher) Close() {
+	d.mutex.Lock()
+	d.closed = true
+	d.mutex.Unlock()
+	d.sending.Wait()
+}
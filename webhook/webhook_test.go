@@ -0,0 +1,194 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bito_interview/storage"
+	"github.com/google/go-cmp/cmp"
+)
+
+var testTime = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+const testBackoff = 10 * time.Millisecond
+
+func TestDeliver(t *testing.T) {
+	event := storage.Event{Type: storage.EventRemoved, PersonID: "1", At: testTime}
+	tests := []struct {
+		name string
+		// responses are the status codes of the receiver, the last one
+		// answering every other request.
+		responses []int
+		events    []storage.EventType
+		want      []Delivery
+		dead      []Delivery
+	}{
+		{
+			name:      "delivered",
+			responses: []int{http.StatusOK},
+			events:    []storage.EventType{storage.EventRemoved},
+			want: []Delivery{{ID: "1", SubscriptionID: "1", Event: event, Status: StatusDelivered, Attempts: []Attempt{
+				{At: testTime, StatusCode: http.StatusOK},
+			}}},
+			dead: []Delivery{},
+		},
+		{
+			name:      "delivered_after_retries",
+			responses: []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusNoContent},
+			events:    []storage.EventType{storage.EventMatched, storage.EventRemoved},
+			want: []Delivery{{ID: "1", SubscriptionID: "1", Event: event, Status: StatusDelivered, Attempts: []Attempt{
+				{At: testTime, StatusCode: http.StatusInternalServerError, Error: "unexpected status 500 Internal Server Error"},
+				{At: testTime, StatusCode: http.StatusServiceUnavailable, Error: "unexpected status 503 Service Unavailable"},
+				{At: testTime, StatusCode: http.StatusNoContent},
+			}}},
+			dead: []Delivery{},
+		},
+		{
+			name:      "dead_letter",
+			responses: []int{http.StatusBadRequest},
+			events:    []storage.EventType{storage.EventRemoved},
+			want: []Delivery{{ID: "1", SubscriptionID: "1", Event: event, Status: StatusDead, Attempts: []Attempt{
+				{At: testTime, StatusCode: http.StatusBadRequest, Error: "unexpected status 400 Bad Request"},
+				{At: testTime, StatusCode: http.StatusBadRequest, Error: "unexpected status 400 Bad Request"},
+				{At: testTime, StatusCode: http.StatusBadRequest, Error: "unexpected status 400 Bad Request"},
+			}}},
+			dead: []Delivery{{ID: "1", SubscriptionID: "1", Event: event, Status: StatusDead, Attempts: []Attempt{
+				{At: testTime, StatusCode: http.StatusBadRequest, Error: "unexpected status 400 Bad Request"},
+				{At: testTime, StatusCode: http.StatusBadRequest, Error: "unexpected status 400 Bad Request"},
+				{At: testTime, StatusCode: http.StatusBadRequest, Error: "unexpected status 400 Bad Request"},
+			}}},
+		},
+		{
+			name:      "other_events",
+			responses: []int{http.StatusOK},
+			events:    []storage.EventType{storage.EventMatched, storage.EventEvicted},
+			want:      []Delivery{},
+			dead:      []Delivery{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var mutex sync.Mutex
+			var received []time.Time
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				payload, _ := io.ReadAll(r.Body)
+				if got, want := r.Header.Get(HeaderSignature), Sign("secret", testTime.Unix(), payload); got != want {
+					t.Errorf("%s got signature %v but want: %v", t.Name(), got, want)
+				}
+				if got, want := r.Header.Get(HeaderTimestamp), strconv.FormatInt(testTime.Unix(), 10); got != want {
+					t.Errorf("%s got timestamp %v but want: %v", t.Name(), got, want)
+				}
+				if got, want := r.Header.Get(HeaderEvent), string(event.Type); got != want {
+					t.Errorf("%s got event %v but want: %v", t.Name(), got, want)
+				}
+				received = append(received, time.Now())
+				w.WriteHeader(test.responses[min(len(received), len(test.responses))-1])
+			}))
+			defer receiver.Close()
+			d := newTestDispatcher()
+			subscription := d.Subscribe(receiver.URL, test.events, "secret")
+			d.Publish(event)
+			d.Close()
+
+			deliveries, err := d.Deliveries(subscription.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(deliveries, test.want); diff != "" {
+				t.Errorf("%s got want:\n%s", t.Name(), diff)
+			}
+			if diff := cmp.Diff(d.DeadLetters(), test.dead); diff != "" {
+				t.Errorf("%s got want:\n%s", t.Name(), diff)
+			}
+			// the wait before every retry is twice as long as the previous one.
+			for i := 1; i < len(received); i++ {
+				if got, want := received[i].Sub(received[i-1]), testBackoff<<(i-1); got < want {
+					t.Errorf("%s got retry %v after %v but want at least: %v", t.Name(), i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		backoff     time.Duration
+		wantAttempt int
+		wantBackoff time.Duration
+	}{
+		{name: "set", maxAttempts: 3, backoff: testBackoff, wantAttempt: 3, wantBackoff: testBackoff},
+		{name: "no_attempt", maxAttempts: 0, backoff: testBackoff, wantAttempt: DefaultMaxAttempts, wantBackoff: testBackoff},
+		{name: "negative", maxAttempts: -1, backoff: -time.Second, wantAttempt: DefaultMaxAttempts, wantBackoff: DefaultBackoff},
+		{name: "capped_backoff", maxAttempts: 1, backoff: time.Hour, wantAttempt: 1, wantBackoff: MaxBackoff},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := newOptions(WithRetries(test.maxAttempts, test.backoff))
+			if got, want := o.maxAttempts, test.wantAttempt; got != want {
+				t.Errorf("%s got %v attempts but want: %v", t.Name(), got, want)
+			}
+			if got, want := o.backoff, test.wantBackoff; got != want {
+				t.Errorf("%s got %v backoff but want: %v", t.Name(), got, want)
+			}
+		})
+	}
+}
+
+func TestDeadLettersCapped(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer receiver.Close()
+	d := NewDispatcher(WithRetries(1, testBackoff))
+	d.maxLogs = 2
+	d.Subscribe(receiver.URL, []storage.EventType{storage.EventRemoved}, "secret")
+	for range 5 {
+		d.Publish(storage.Event{Type: storage.EventRemoved, PersonID: "1", At: testTime})
+	}
+	d.Close()
+	if got, want := len(d.DeadLetters()), 2; got != want {
+		t.Errorf("%s got %v dead letters but want: %v", t.Name(), got, want)
+	}
+}
+
+func TestSubscriptions(t *testing.T) {
+	d := newTestDispatcher()
+	defer d.Close()
+	subscription := d.Subscribe("http://localhost/hook", []storage.EventType{storage.EventMatched}, "secret")
+	want := []Subscription{{ID: "1", URL: "http://localhost/hook", Events: []storage.EventType{storage.EventMatched}, Secret: "secret", CreatedAt: testTime}}
+	if diff := cmp.Diff(d.Subscriptions(), want); diff != "" {
+		t.Errorf("%s got want:\n%s", t.Name(), diff)
+	}
+	if err := d.Unsubscribe(subscription.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Unsubscribe(subscription.ID); err != ErrNotFound {
+		t.Errorf("%s got %v but want: %v", t.Name(), err, ErrNotFound)
+	}
+	if _, err := d.Deliveries(subscription.ID); err != ErrNotFound {
+		t.Errorf("%s got %v but want: %v", t.Name(), err, ErrNotFound)
+	}
+	if diff := cmp.Diff(d.Subscriptions(), []Subscription{}); diff != "" {
+		t.Errorf("%s got want:\n%s", t.Name(), diff)
+	}
+}
+
+func newTestDispatcher() *Dispatcher {
+	return NewDispatcher(
+		WithIDGenerator(storage.FakeIDGenerator{FakeID: "1"}),
+		WithClock(func() time.Time { return testTime }),
+		WithRetries(3, testBackoff),
+	)
+}